@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"net"
@@ -10,25 +11,79 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/awesomeapibrasil/gateway-worker/internal/certificate"
+	"github.com/awesomeapibrasil/gateway-worker/internal/config"
 	"github.com/awesomeapibrasil/gateway-worker/internal/grpc"
 	"github.com/awesomeapibrasil/gateway-worker/internal/health"
+	"github.com/awesomeapibrasil/gateway-worker/internal/integration"
 	"github.com/awesomeapibrasil/gateway-worker/internal/queue"
+	"github.com/awesomeapibrasil/gateway-worker/internal/storage"
+	"github.com/awesomeapibrasil/gateway-worker/internal/tracing"
 )
 
 const (
-	defaultGRPCPort = "8080"
-	defaultHTTPPort = "8081"
+	defaultGRPCPort       = "8080"
+	defaultHTTPPort       = "8081"
+	defaultStorageBackend = "fs"
+	defaultQueueBackend   = "kv"
+
+	databaseCleanupSchedule = "0 3 * * *" // nightly at 03:00
+	analyticsRollupSchedule = "0 4 * * 0" // weekly, Sunday at 04:00
+)
+
+// certStorage and configStorage are populated by initStorage and consumed
+// once certificate.New/config.New gain concrete Validator/Distributor
+// implementations to pair them with.
+var (
+	certStorage   certificate.Storage
+	configStorage config.Storage
 )
 
 func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	shutdownTracing, err := tracing.Init(ctx, "gateway-worker")
+	if err != nil {
+		log.Fatalf("Failed to initialize tracing: %v", err)
+	}
+	defer func() {
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer shutdownCancel()
+		if err := shutdownTracing(shutdownCtx); err != nil {
+			log.Printf("Failed to shut down tracing: %v", err)
+		}
+	}()
+
+	// Initialize storage backend
+	kv, err := initStorage(ctx)
+	if err != nil {
+		log.Fatalf("Failed to initialize storage: %v", err)
+	}
+	defer kv.Close()
+
 	// Initialize services
 	healthService := health.New()
-	queueService := queue.New()
+	queueService, closeQueue, err := initQueue(ctx, kv)
+	if err != nil {
+		log.Fatalf("Failed to initialize queue: %v", err)
+	}
+	defer closeQueue()
 	grpcService := grpc.New(queueService)
 
+	if err := healthService.Metrics().Register("queue/jobs", queueService.Metrics().Collectors()...); err != nil {
+		log.Printf("Failed to register queue metrics: %v", err)
+	}
+
+	if err := queueService.AddPeriodicJob("database-cleanup", databaseCleanupSchedule, queue.Job{Type: queue.JobTypeDatabaseCleanup}); err != nil {
+		log.Printf("Failed to register periodic database cleanup job: %v", err)
+	}
+	if err := queueService.AddPeriodicJob("analytics-rollup", analyticsRollupSchedule, queue.Job{Type: queue.JobTypeAnalytics}); err != nil {
+		log.Printf("Failed to register periodic analytics job: %v", err)
+	}
+
+	initIntegrationHandlers(kv, queueService)
+
 	// Start gRPC server
 	grpcPort := getEnv("GRPC_PORT", defaultGRPCPort)
 	go func() {
@@ -84,9 +139,104 @@ func startHTTPServer(ctx context.Context, healthService *health.Service, port st
 	return healthService.Serve(ctx, ":"+port)
 }
 
+// initStorage opens the KV backend named by STORAGE_BACKEND (one of the
+// drivers registered in internal/storage; "fs" if unset) and wraps it as the
+// certificate/config Storage implementations the respective managers need.
+func initStorage(ctx context.Context) (storage.KV, error) {
+	backend := getEnv("STORAGE_BACKEND", defaultStorageBackend)
+	dsn := getEnv("STORAGE_DSN", "")
+
+	kv, err := storage.Open(ctx, backend, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open storage backend %q: %w", backend, err)
+	}
+
+	certStorage = storage.NewCertificateStore(kv)
+	configStorage = storage.NewConfigurationStore(kv)
+
+	log.Printf("Storage backend %q ready", backend)
+	return kv, nil
+}
+
+// initQueue constructs the queue.Service backend named by QUEUE_BACKEND:
+// "kv" (the default) reuses the storage backend opened by initStorage above,
+// and is the only option suited to a single-instance deployment; "redis"
+// connects to a dedicated broker at QUEUE_REDIS_DSN for deployments running
+// several worker replicas against one queue. The returned close func
+// releases any connection the backend opened and is always non-nil.
+func initQueue(ctx context.Context, kv storage.KV) (*queue.Service, func(), error) {
+	backend := getEnv("QUEUE_BACKEND", defaultQueueBackend)
+
+	switch backend {
+	case "kv":
+		return queue.New(kv), func() {}, nil
+	case "redis":
+		dsn := getEnv("QUEUE_REDIS_DSN", "")
+		redisBackend, err := queue.NewRedisBackend(ctx, dsn)
+		if err != nil {
+			return nil, nil, fmt.Errorf("connect queue redis backend: %w", err)
+		}
+		log.Println("Queue backend \"redis\" ready")
+		return queue.NewWithBackend(redisBackend), func() { redisBackend.Close() }, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown queue backend %q", backend)
+	}
+}
+
+// queueJobEnqueuer adapts *queue.Service to integration.JobEnqueuer, so
+// WebhookDeliverer can retry a failed delivery through the queue without
+// this package's integration import becoming a cycle (internal/queue must
+// not import internal/integration; see queue.Service's Notifier doc comment).
+type queueJobEnqueuer struct {
+	queue *queue.Service
+}
+
+func (q queueJobEnqueuer) Submit(ctx context.Context, jobType string, payload map[string]interface{}) error {
+	return q.queue.Submit(ctx, &queue.Job{Type: queue.JobType(jobType), Payload: payload})
+}
+
+// initIntegrationHandlers wires the queue handlers that integration features
+// depend on but that internal/queue can't register itself (it must not
+// import internal/integration, to avoid a queue -> storage -> integration ->
+// queue cycle): redelivering retried webhooks and polling threat feeds.
+// Threat feeds aren't scheduled anywhere yet - AddPeriodicJob(
+// "threat-feed-poll-<name>", ..., queue.Job{Type: queue.JobTypeThreatFeedPoll,
+// Payload: <integration.FeedSource as a map>}) is how a caller would
+// register one.
+func initIntegrationHandlers(kv storage.KV, queueService *queue.Service) {
+	deliverer := integration.NewWebhookDeliverer(queueJobEnqueuer{queue: queueService}, storage.NewWebhookLedgerStore(kv))
+	feedProcessor := integration.NewFeedProcessor(storage.NewFeedCursorStore(kv))
+	manager := integration.New(deliverer, deliverer, feedProcessor, nil)
+
+	queueService.RegisterHandler(queue.JobTypeIntegration, "deliverWebhook", queue.JobHandlerFunc(func(ctx context.Context, job queue.Job) error {
+		return deliverer.HandleRetryJob(ctx, job.Payload)
+	}))
+
+	queueService.RegisterHandler(queue.JobTypeThreatFeedPoll, "processThreatFeedPoll", queue.JobHandlerFunc(func(ctx context.Context, job queue.Job) error {
+		source, err := decodeFeedSource(job.Payload)
+		if err != nil {
+			return fmt.Errorf("decode feed source from job %s: %w", job.ID, err)
+		}
+		_, err = manager.ProcessSecurityFeeds(ctx, []integration.FeedSource{source})
+		return err
+	}))
+}
+
+// decodeFeedSource round-trips payload (a job's generic
+// map[string]interface{}) through JSON into an integration.FeedSource.
+func decodeFeedSource(payload map[string]interface{}) (integration.FeedSource, error) {
+	var source integration.FeedSource
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return source, err
+	}
+	err = json.Unmarshal(data, &source)
+	return source, err
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
 	}
 	return defaultValue
-}
\ No newline at end of file
+}
@@ -0,0 +1,42 @@
+package predict
+
+import "math"
+
+// fitConfidence scores how closely fitted tracked the tail of series (its
+// one-step-ahead predictions) as 1 minus the RMSE normalized by the mean
+// absolute actual value, clamped to [0, 1]. fitted is shorter than series
+// by however many points the model needed to warm up (a season for
+// Holt-Winters, one point for Holt's linear method); only the overlapping
+// tail is compared.
+func fitConfidence(series, fitted []float64) float64 {
+	offset := len(series) - len(fitted)
+	if offset < 0 || len(fitted) == 0 {
+		return 0
+	}
+
+	var sumSqErr, sumAbs float64
+	for i, f := range fitted {
+		actual := series[offset+i]
+		diff := actual - f
+		sumSqErr += diff * diff
+		sumAbs += math.Abs(actual)
+	}
+
+	meanAbs := sumAbs / float64(len(fitted))
+	if meanAbs == 0 {
+		return 0
+	}
+
+	rmse := math.Sqrt(sumSqErr / float64(len(fitted)))
+	return clamp(1-rmse/meanAbs, 0, 1)
+}
+
+func clamp(x, lo, hi float64) float64 {
+	if x < lo {
+		return lo
+	}
+	if x > hi {
+		return hi
+	}
+	return x
+}
@@ -0,0 +1,46 @@
+package predict
+
+import "fmt"
+
+// desState holds a Holt's linear (double exponential smoothing) fit: a
+// level and trend, with no seasonal component. It's the fallback used when
+// there isn't enough history for a full Holt-Winters seasonal fit.
+type desState struct {
+	level  float64
+	trend  float64
+	fitted []float64
+}
+
+// fitDoubleExponential fits Holt's linear trend method to series.
+func fitDoubleExponential(series []float64, alpha, beta float64) (*desState, error) {
+	if len(series) < 2 {
+		return nil, fmt.Errorf("predict: need at least 2 points, got %d", len(series))
+	}
+
+	level := series[0]
+	trend := series[1] - series[0]
+	fitted := make([]float64, 0, len(series)-1)
+
+	for _, y := range series[1:] {
+		fitted = append(fitted, level+trend)
+
+		prevLevel := level
+		level = alpha*y + (1-alpha)*(level+trend)
+		trend = beta*(level-prevLevel) + (1-beta)*trend
+	}
+
+	return &desState{level: level, trend: trend, fitted: fitted}, nil
+}
+
+// forecast returns the next horizon points beyond the fitted series.
+func (s *desState) forecast(horizon int) []float64 {
+	out := make([]float64, horizon)
+	for h := 1; h <= horizon; h++ {
+		out[h-1] = s.level + float64(h)*s.trend
+	}
+	return out
+}
+
+func (s *desState) lastTrend() float64 {
+	return s.trend
+}
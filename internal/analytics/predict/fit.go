@@ -0,0 +1,91 @@
+package predict
+
+// gridMin, gridMax, and gridStep bound the auto-fit search used when Config
+// leaves Alpha/Beta/Gamma at zero: each parameter is swept over
+// [gridMin, gridMax] in gridStep increments, and the combination minimizing
+// SSE against the observed series is kept.
+const (
+	gridMin  = 0.05
+	gridMax  = 0.95
+	gridStep = 0.05
+)
+
+// holtWinters fits an additive Holt-Winters model to series, using p.cfg's
+// Alpha/Beta/Gamma if any is set, or grid-searching all three for the
+// SSE-minimizing combination otherwise.
+func (p *Predictor) holtWinters(series []float64) (*hwState, error) {
+	if p.cfg.Alpha != 0 || p.cfg.Beta != 0 || p.cfg.Gamma != 0 {
+		alpha, beta, gamma := p.cfg.Alpha, p.cfg.Beta, p.cfg.Gamma
+		if alpha == 0 {
+			alpha = 0.3
+		}
+		if beta == 0 {
+			beta = 0.1
+		}
+		if gamma == 0 {
+			gamma = 0.3
+		}
+		return fitHoltWinters(series, p.cfg.SeasonLength, alpha, beta, gamma)
+	}
+
+	var best *hwState
+	var bestSSE float64
+	for alpha := gridMin; alpha <= gridMax+1e-9; alpha += gridStep {
+		for beta := gridMin; beta <= gridMax+1e-9; beta += gridStep {
+			for gamma := gridMin; gamma <= gridMax+1e-9; gamma += gridStep {
+				fit, err := fitHoltWinters(series, p.cfg.SeasonLength, alpha, beta, gamma)
+				if err != nil {
+					return nil, err
+				}
+				if sse := sumSquaredError(series, fit.fitted); best == nil || sse < bestSSE {
+					best, bestSSE = fit, sse
+				}
+			}
+		}
+	}
+	return best, nil
+}
+
+// doubleExponential fits Holt's linear trend method to series, using p.cfg's
+// Alpha/Beta if either is set, or grid-searching both for the
+// SSE-minimizing combination otherwise.
+func (p *Predictor) doubleExponential(series []float64) (*desState, error) {
+	if p.cfg.Alpha != 0 || p.cfg.Beta != 0 {
+		alpha, beta := p.cfg.Alpha, p.cfg.Beta
+		if alpha == 0 {
+			alpha = 0.3
+		}
+		if beta == 0 {
+			beta = 0.1
+		}
+		return fitDoubleExponential(series, alpha, beta)
+	}
+
+	var best *desState
+	var bestSSE float64
+	for alpha := gridMin; alpha <= gridMax+1e-9; alpha += gridStep {
+		for beta := gridMin; beta <= gridMax+1e-9; beta += gridStep {
+			fit, err := fitDoubleExponential(series, alpha, beta)
+			if err != nil {
+				return nil, err
+			}
+			if sse := sumSquaredError(series, fit.fitted); best == nil || sse < bestSSE {
+				best, bestSSE = fit, sse
+			}
+		}
+	}
+	return best, nil
+}
+
+// sumSquaredError compares fitted (a one-step-ahead fit, shorter than
+// series by however many points the model needed to warm up) against the
+// trailing, overlapping window of series.
+func sumSquaredError(series, fitted []float64) float64 {
+	offset := len(series) - len(fitted)
+	var sse float64
+	for i, f := range fitted {
+		diff := series[offset+i] - f
+		sse += diff * diff
+	}
+	return sse
+}
@@ -0,0 +1,78 @@
+package predict
+
+import "fmt"
+
+// hwState holds an additive Holt-Winters (triple exponential smoothing) fit
+// over a full observed series: a level, trend, and seasonal component per
+// time step, plus the one-step-ahead fitted values used to score Confidence.
+type hwState struct {
+	level    []float64
+	trend    []float64
+	seasonal []float64
+	fitted   []float64
+	season   int
+}
+
+// fitHoltWinters fits an additive Holt-Winters model to series. It requires
+// at least two full seasons of history; shorter series should fall back to
+// fitDoubleExponential instead.
+func fitHoltWinters(series []float64, season int, alpha, beta, gamma float64) (*hwState, error) {
+	if season < 2 {
+		return nil, fmt.Errorf("predict: season length must be >= 2, got %d", season)
+	}
+	if len(series) < 2*season {
+		return nil, fmt.Errorf("predict: need at least %d points for season length %d, got %d", 2*season, season, len(series))
+	}
+
+	n := len(series)
+	level := make([]float64, n)
+	trend := make([]float64, n)
+	seasonal := make([]float64, n)
+	fitted := make([]float64, 0, n-season)
+
+	firstSeasonAvg := average(series[:season])
+	secondSeasonAvg := average(series[season : 2*season])
+
+	level[season-1] = firstSeasonAvg
+	trend[season-1] = (secondSeasonAvg - firstSeasonAvg) / float64(season)
+	for i := 0; i < season; i++ {
+		seasonal[i] = series[i] - firstSeasonAvg
+	}
+
+	for t := season; t < n; t++ {
+		prevLevel, prevTrend := level[t-1], trend[t-1]
+		fitted = append(fitted, prevLevel+prevTrend+seasonal[t-season])
+
+		level[t] = alpha*(series[t]-seasonal[t-season]) + (1-alpha)*(prevLevel+prevTrend)
+		trend[t] = beta*(level[t]-prevLevel) + (1-beta)*prevTrend
+		seasonal[t] = gamma*(series[t]-level[t]) + (1-gamma)*seasonal[t-season]
+	}
+
+	return &hwState{level: level, trend: trend, seasonal: seasonal, fitted: fitted, season: season}, nil
+}
+
+// forecast returns the next horizon points beyond the fitted series,
+// cycling the last observed season's components for the seasonal term.
+func (s *hwState) forecast(horizon int) []float64 {
+	n := len(s.level)
+	lastLevel, lastTrend := s.level[n-1], s.trend[n-1]
+
+	out := make([]float64, horizon)
+	for h := 1; h <= horizon; h++ {
+		seasonalIdx := n - s.season + (h-1)%s.season
+		out[h-1] = lastLevel + float64(h)*lastTrend + s.seasonal[seasonalIdx]
+	}
+	return out
+}
+
+func (s *hwState) lastTrend() float64 {
+	return s.trend[len(s.trend)-1]
+}
+
+func average(xs []float64) float64 {
+	var sum float64
+	for _, x := range xs {
+		sum += x
+	}
+	return sum / float64(len(xs))
+}
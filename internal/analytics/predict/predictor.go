@@ -0,0 +1,187 @@
+// Package predict implements analytics.Predictor with Holt-Winters triple
+// exponential smoothing, falling back to Holt's linear (double exponential)
+// smoothing when there isn't enough history for a full seasonal cycle.
+package predict
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/awesomeapibrasil/gateway-worker/internal/analytics"
+)
+
+// Config configures a Predictor.
+type Config struct {
+	// SeasonLength is the number of points per seasonal cycle (e.g. 24 for
+	// hourly samples over a daily cycle). Holt-Winters is only used once
+	// historical holds at least two full seasons; shorter history falls
+	// back to Holt's linear method. Zero defaults to 24.
+	SeasonLength int
+	// Horizon is how many points ahead PredictTraffic forecasts. Zero
+	// defaults to SeasonLength.
+	Horizon int
+	// Alpha, Beta, Gamma are the level/trend/seasonal smoothing factors,
+	// each in (0, 1]. If all three are zero, PredictTraffic auto-fits them
+	// per call by grid search over [0.05, 0.95] in 0.05 steps, minimizing
+	// SSE against the historical series (see holtWinters/doubleExponential
+	// in fit.go). For Holt-Winters, setting any one of them opts out of
+	// auto-fit; Holt's linear method opts out only when Alpha or Beta is set.
+	// Unset factors then default to 0.3/0.1/0.3.
+	Alpha, Beta, Gamma float64
+	// CapacityHeadroom scales PredictCapacity's recommended capacity above
+	// the predicted peak load. Zero defaults to 1.2 (20% headroom).
+	CapacityHeadroom float64
+}
+
+func (c Config) withDefaults() Config {
+	if c.SeasonLength <= 0 {
+		c.SeasonLength = 24
+	}
+	if c.Horizon <= 0 {
+		c.Horizon = c.SeasonLength
+	}
+	if c.CapacityHeadroom == 0 {
+		c.CapacityHeadroom = 1.2
+	}
+	return c
+}
+
+// Predictor implements analytics.Predictor via exponential smoothing.
+type Predictor struct {
+	cfg Config
+}
+
+// New builds a Predictor from cfg, applying defaults for zero fields.
+func New(cfg Config) *Predictor {
+	return &Predictor{cfg: cfg.withDefaults()}
+}
+
+// PredictTraffic forecasts cfg.Horizon points beyond historical. It uses
+// Holt-Winters when historical spans at least two full seasons, and falls
+// back to Holt's linear method otherwise. historical must be ordered
+// oldest-first and span a consistent interval.
+func (p *Predictor) PredictTraffic(ctx context.Context, historical []analytics.TimeSeriesPoint) (*analytics.TrafficPrediction, error) {
+	if len(historical) < 2 {
+		return nil, fmt.Errorf("predict: need at least 2 historical points, got %d", len(historical))
+	}
+
+	series, interval := resample(historical)
+
+	var forecastValues []float64
+	var trendSlope, confidence float64
+
+	if hw, err := p.holtWinters(series); err == nil {
+		forecastValues = hw.forecast(p.cfg.Horizon)
+		trendSlope = hw.lastTrend()
+		confidence = fitConfidence(series, hw.fitted)
+	} else {
+		des, desErr := p.doubleExponential(series)
+		if desErr != nil {
+			return nil, desErr
+		}
+		forecastValues = des.forecast(p.cfg.Horizon)
+		trendSlope = des.lastTrend()
+		confidence = fitConfidence(series, des.fitted)
+	}
+
+	last := historical[len(historical)-1].Timestamp
+	predictions := make([]analytics.TimeSeriesPoint, len(forecastValues))
+	for i, v := range forecastValues {
+		predictions[i] = analytics.TimeSeriesPoint{
+			Timestamp: last.Add(time.Duration(i+1) * interval),
+			Value:     v,
+		}
+	}
+	period := time.Duration(len(forecastValues)) * interval
+
+	return &analytics.TrafficPrediction{
+		Period:      period,
+		Confidence:  confidence,
+		Predictions: predictions,
+		Trends:      []analytics.Trend{classifyTrend(trendSlope, average(series), period, confidence)},
+	}, nil
+}
+
+// PredictCapacity forecasts metrics' traffic forward and recommends a
+// capacity that covers the predicted peak with cfg.CapacityHeadroom to
+// spare. metrics need not be sorted by time.
+func (p *Predictor) PredictCapacity(ctx context.Context, metrics []analytics.Metric) (*analytics.CapacityPrediction, error) {
+	if len(metrics) == 0 {
+		return nil, fmt.Errorf("predict: need at least 1 metric, got 0")
+	}
+
+	sorted := append([]analytics.Metric(nil), metrics...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Timestamp.Before(sorted[j].Timestamp) })
+
+	historical := make([]analytics.TimeSeriesPoint, len(sorted))
+	for i, m := range sorted {
+		historical[i] = analytics.TimeSeriesPoint{Timestamp: m.Timestamp, Value: m.Value}
+	}
+
+	prediction, err := p.PredictTraffic(ctx, historical)
+	if err != nil {
+		return nil, fmt.Errorf("predict capacity: %w", err)
+	}
+
+	var peak float64
+	for _, pt := range prediction.Predictions {
+		if pt.Value > peak {
+			peak = pt.Value
+		}
+	}
+
+	return &analytics.CapacityPrediction{
+		Period:                prediction.Period,
+		PredictedLoad:         peak,
+		RecommendedCapacity:   peak * p.cfg.CapacityHeadroom,
+		ScalingRecommendation: scalingRecommendation(prediction.Trends[0]),
+		Confidence:            prediction.Confidence,
+	}, nil
+}
+
+// classifyTrend labels slope as increasing/decreasing/stable relative to
+// avgLevel, treating moves under 1% of the average per step as noise.
+func classifyTrend(slope, avgLevel float64, period time.Duration, confidence float64) analytics.Trend {
+	threshold := 0.01 * avgLevel
+	if threshold < 0 {
+		threshold = -threshold
+	}
+
+	trendType := "stable"
+	switch {
+	case slope > threshold:
+		trendType = "increasing"
+	case slope < -threshold:
+		trendType = "decreasing"
+	}
+
+	return analytics.Trend{
+		Type:       trendType,
+		Slope:      slope,
+		Confidence: confidence,
+		Period:     period,
+	}
+}
+
+func scalingRecommendation(trend analytics.Trend) string {
+	switch trend.Type {
+	case "increasing":
+		return "scale_up"
+	case "decreasing":
+		return "scale_down"
+	default:
+		return "stable"
+	}
+}
+
+// sampleInterval returns the average gap between consecutive points, used
+// to project forecast timestamps beyond the last observed one.
+func sampleInterval(points []analytics.TimeSeriesPoint) time.Duration {
+	if len(points) < 2 {
+		return time.Minute
+	}
+	total := points[len(points)-1].Timestamp.Sub(points[0].Timestamp)
+	return total / time.Duration(len(points)-1)
+}
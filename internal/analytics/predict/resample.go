@@ -0,0 +1,67 @@
+package predict
+
+import (
+	"time"
+
+	"github.com/awesomeapibrasil/gateway-worker/internal/analytics"
+)
+
+// minPositiveValue is the floor resample applies to zero or negative points
+// (e.g. a gap recorded as 0 req/s) before smoothing.
+const minPositiveValue = 1e-6
+
+// resample projects historical onto a uniform grid at its average sample
+// interval, linearly interpolating any grid point that falls between two
+// observations, and flooring non-positive values to minPositiveValue.
+// historical must be ordered oldest-first and have at least 2 points.
+func resample(historical []analytics.TimeSeriesPoint) ([]float64, time.Duration) {
+	interval := sampleInterval(historical)
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	steps := int(historical[len(historical)-1].Timestamp.Sub(historical[0].Timestamp)/interval) + 1
+	if steps < len(historical) {
+		steps = len(historical)
+	}
+
+	series := make([]float64, steps)
+	start := historical[0].Timestamp
+	for i := 0; i < steps; i++ {
+		series[i] = clampPositive(interpolate(historical, start.Add(time.Duration(i)*interval)))
+	}
+	return series, interval
+}
+
+// interpolate linearly interpolates historical's value at ts between the
+// two points surrounding it, clamping to the first/last point if ts falls
+// outside their range.
+func interpolate(historical []analytics.TimeSeriesPoint, ts time.Time) float64 {
+	if !ts.After(historical[0].Timestamp) {
+		return historical[0].Value
+	}
+	last := historical[len(historical)-1]
+	if !ts.Before(last.Timestamp) {
+		return last.Value
+	}
+
+	for i := 1; i < len(historical); i++ {
+		if historical[i].Timestamp.After(ts) {
+			prev, next := historical[i-1], historical[i]
+			span := next.Timestamp.Sub(prev.Timestamp)
+			if span <= 0 {
+				return prev.Value
+			}
+			frac := ts.Sub(prev.Timestamp).Seconds() / span.Seconds()
+			return prev.Value + frac*(next.Value-prev.Value)
+		}
+	}
+	return last.Value
+}
+
+func clampPositive(v float64) float64 {
+	if v <= 0 {
+		return minPositiveValue
+	}
+	return v
+}
@@ -0,0 +1,55 @@
+package prom
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// Alert is one entry from /api/v1/alerts.
+type Alert struct {
+	Labels      map[string]string
+	Annotations map[string]string
+	State       string
+	ActiveAt    time.Time
+	Value       string
+}
+
+type alertsData struct {
+	Alerts []rawAlert `json:"alerts"`
+}
+
+type rawAlert struct {
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+	State       string            `json:"state"`
+	ActiveAt    time.Time         `json:"activeAt"`
+	Value       string            `json:"value"`
+}
+
+// Alerts fetches currently active alerts via /api/v1/alerts.
+func (c *Client) Alerts(ctx context.Context) ([]Alert, Warnings, error) {
+	data, warnings, err := c.get(ctx, "/api/v1/alerts", url.Values{})
+	if err != nil {
+		return nil, warnings, err
+	}
+
+	var ad alertsData
+	if err := json.Unmarshal(data, &ad); err != nil {
+		return nil, warnings, fmt.Errorf("decode alerts result: %w", err)
+	}
+
+	alerts := make([]Alert, 0, len(ad.Alerts))
+	for _, a := range ad.Alerts {
+		alerts = append(alerts, Alert{
+			Labels:      a.Labels,
+			Annotations: a.Annotations,
+			State:       a.State,
+			ActiveAt:    a.ActiveAt,
+			Value:       a.Value,
+		})
+	}
+	return alerts, warnings, nil
+}
@@ -0,0 +1,58 @@
+package prom
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/awesomeapibrasil/gateway-worker/internal/analytics"
+)
+
+// AlertsCollector converts Prometheus active alerts into analytics.Event
+// records, so alerting state flows into the same reporting pipeline as
+// metrics.
+type AlertsCollector struct {
+	client *Client
+}
+
+// NewAlertsCollector builds an AlertsCollector around client.
+func NewAlertsCollector(client *Client) *AlertsCollector {
+	return &AlertsCollector{client: client}
+}
+
+// CollectEvents fetches active alerts via /api/v1/alerts and converts each
+// into an Event, with Severity taken from the alert's "severity" label.
+// source is unused; it exists to satisfy analytics.Collector.CollectEvents.
+func (a *AlertsCollector) CollectEvents(ctx context.Context, source string) ([]analytics.Event, error) {
+	alerts, warnings, err := a.client.Alerts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("prom alerts collector: %w", err)
+	}
+	logWarnings("CollectEvents", "alerts", warnings)
+
+	events := make([]analytics.Event, 0, len(alerts))
+	for _, alert := range alerts {
+		events = append(events, analytics.Event{
+			ID:         alert.Labels["alertname"] + "@" + alert.ActiveAt.Format("20060102T150405"),
+			Type:       "alert",
+			Timestamp:  alert.ActiveAt,
+			Source:     "prometheus",
+			Severity:   alert.Labels["severity"],
+			Message:    alert.Annotations["summary"],
+			Attributes: alertAttributes(alert),
+		})
+	}
+	return events, nil
+}
+
+func alertAttributes(alert Alert) map[string]interface{} {
+	attrs := make(map[string]interface{}, len(alert.Labels)+len(alert.Annotations)+2)
+	for k, v := range alert.Labels {
+		attrs["label."+k] = v
+	}
+	for k, v := range alert.Annotations {
+		attrs["annotation."+k] = v
+	}
+	attrs["state"] = alert.State
+	attrs["value"] = alert.Value
+	return attrs
+}
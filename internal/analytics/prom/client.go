@@ -0,0 +1,122 @@
+// Package prom implements analytics.Collector against a Prometheus server's
+// HTTP API v1 (https://prometheus.io/docs/prometheus/latest/querying/api/).
+package prom
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config configures a Client.
+type Config struct {
+	BaseURL string
+
+	// BearerToken, if set, is sent as "Authorization: Bearer <token>".
+	BearerToken string
+	// BasicAuthUser/BasicAuthPass authenticate via HTTP basic auth.
+	// Ignored when BearerToken is set.
+	BasicAuthUser string
+	BasicAuthPass string
+
+	TLSClientConfig *tls.Config
+
+	// Timeout bounds each HTTP request. Defaults to 10s.
+	Timeout time.Duration
+	// QueryTimeout, if set, is sent as the Prometheus-specific
+	// X-Prometheus-Query-Timeout header (seconds) so the server can abort
+	// an expensive query server-side, independent of Timeout.
+	QueryTimeout time.Duration
+}
+
+// Warnings are non-fatal issues the Prometheus API reported alongside a
+// successful response (e.g. a partial scrape failure), surfaced to callers
+// instead of being silently dropped.
+type Warnings []string
+
+// Client queries a Prometheus server's HTTP API v1.
+type Client struct {
+	cfg        Config
+	httpClient *http.Client
+}
+
+// NewClient builds a Client from cfg.
+func NewClient(cfg Config) (*Client, error) {
+	if cfg.BaseURL == "" {
+		return nil, fmt.Errorf("prom: BaseURL is required")
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+
+	var transport http.RoundTripper = http.DefaultTransport
+	if cfg.TLSClientConfig != nil {
+		transport = &http.Transport{TLSClientConfig: cfg.TLSClientConfig}
+	}
+
+	return &Client{
+		cfg: cfg,
+		httpClient: &http.Client{
+			Timeout:   cfg.Timeout,
+			Transport: transport,
+		},
+	}, nil
+}
+
+type apiResponse struct {
+	Status    string          `json:"status"`
+	Data      json.RawMessage `json:"data"`
+	ErrorType string          `json:"errorType"`
+	Error     string          `json:"error"`
+	Warnings  []string        `json:"warnings"`
+}
+
+// get issues a GET against path with query, and unwraps the Prometheus API's
+// status/data/warnings envelope.
+func (c *Client) get(ctx context.Context, path string, query url.Values) (json.RawMessage, Warnings, error) {
+	reqURL := strings.TrimRight(c.cfg.BaseURL, "/") + path + "?" + query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("build request: %w", err)
+	}
+	if c.cfg.QueryTimeout > 0 {
+		req.Header.Set("X-Prometheus-Query-Timeout", strconv.FormatFloat(c.cfg.QueryTimeout.Seconds(), 'f', -1, 64))
+	}
+	c.authenticate(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("prometheus request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var apiResp apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return nil, nil, fmt.Errorf("decode prometheus response: %w", err)
+	}
+
+	if apiResp.Status != "success" {
+		return nil, Warnings(apiResp.Warnings), fmt.Errorf("prometheus query failed (%s): %s", apiResp.ErrorType, apiResp.Error)
+	}
+	return apiResp.Data, Warnings(apiResp.Warnings), nil
+}
+
+func (c *Client) authenticate(req *http.Request) {
+	switch {
+	case c.cfg.BearerToken != "":
+		req.Header.Set("Authorization", "Bearer "+c.cfg.BearerToken)
+	case c.cfg.BasicAuthUser != "":
+		req.SetBasicAuth(c.cfg.BasicAuthUser, c.cfg.BasicAuthPass)
+	}
+}
+
+func formatTimestamp(t time.Time) string {
+	return strconv.FormatFloat(float64(t.UnixNano())/1e9, 'f', -1, 64)
+}
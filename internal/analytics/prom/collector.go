@@ -0,0 +1,68 @@
+package prom
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/awesomeapibrasil/gateway-worker/internal/analytics"
+)
+
+// Collector implements analytics.Collector against a Prometheus server. The
+// source argument each method takes is the PromQL expression to evaluate
+// (e.g. "rate(http_requests_total[5m])"), not a literal data source name.
+type Collector struct {
+	client *Client
+	alerts *AlertsCollector
+}
+
+// NewCollector builds a Collector (and the AlertsCollector it delegates
+// CollectEvents to) around client.
+func NewCollector(client *Client) *Collector {
+	return &Collector{client: client, alerts: NewAlertsCollector(client)}
+}
+
+// CollectMetrics runs source as an instant PromQL query and translates the
+// resulting vector into analytics.Metric values, one per labeled series.
+// Warnings the Prometheus API returns alongside a successful response are
+// logged rather than silently dropped; use Client.InstantQuery directly if
+// the caller needs them returned.
+func (c *Collector) CollectMetrics(ctx context.Context, source string) ([]analytics.Metric, error) {
+	samples, warnings, err := c.client.InstantQuery(ctx, source, time.Time{})
+	if err != nil {
+		return nil, fmt.Errorf("prom collector: query %q: %w", source, err)
+	}
+	logWarnings("CollectMetrics", source, warnings)
+
+	metrics := make([]analytics.Metric, 0, len(samples))
+	for _, s := range samples {
+		metrics = append(metrics, analytics.Metric{
+			Name:      metricName(s.Metric),
+			Value:     s.Value,
+			Timestamp: s.Timestamp,
+			Source:    source,
+			Tags:      s.Metric,
+		})
+	}
+	return metrics, nil
+}
+
+// CollectEvents delegates to the AlertsCollector; source is ignored since
+// Prometheus active alerts aren't partitioned by an arbitrary source string.
+func (c *Collector) CollectEvents(ctx context.Context, source string) ([]analytics.Event, error) {
+	return c.alerts.CollectEvents(ctx, source)
+}
+
+func metricName(labels map[string]string) string {
+	if name, ok := labels["__name__"]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+func logWarnings(method, source string, warnings Warnings) {
+	for _, w := range warnings {
+		log.Printf("prom collector: %s(%q): warning: %s", method, source, w)
+	}
+}
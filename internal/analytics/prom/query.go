@@ -0,0 +1,167 @@
+package prom
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/awesomeapibrasil/gateway-worker/internal/analytics"
+)
+
+// Sample is one instant-query result: a labeled series evaluated at a
+// single point in time.
+type Sample struct {
+	Metric    map[string]string
+	Timestamp time.Time
+	Value     float64
+}
+
+// Series is one range-query result: a labeled series with its points over
+// the queried window.
+type Series struct {
+	Metric map[string]string
+	Points []analytics.TimeSeriesPoint
+}
+
+type queryData struct {
+	ResultType string          `json:"resultType"`
+	Result     json.RawMessage `json:"result"`
+}
+
+type vectorResult struct {
+	Metric map[string]string `json:"metric"`
+	Value  [2]interface{}    `json:"value"`
+}
+
+type matrixResult struct {
+	Metric map[string]string `json:"metric"`
+	Values [][2]interface{}  `json:"values"`
+}
+
+// InstantQuery evaluates query via /api/v1/query, at ts or at the server's
+// current time if ts is zero.
+func (c *Client) InstantQuery(ctx context.Context, query string, ts time.Time) ([]Sample, Warnings, error) {
+	params := url.Values{"query": {query}}
+	if !ts.IsZero() {
+		params.Set("time", formatTimestamp(ts))
+	}
+
+	data, warnings, err := c.get(ctx, "/api/v1/query", params)
+	if err != nil {
+		return nil, warnings, err
+	}
+
+	var qd queryData
+	if err := json.Unmarshal(data, &qd); err != nil {
+		return nil, warnings, fmt.Errorf("decode query result: %w", err)
+	}
+	if qd.ResultType != "vector" {
+		return nil, warnings, fmt.Errorf("unexpected result type %q for instant query", qd.ResultType)
+	}
+
+	var results []vectorResult
+	if err := json.Unmarshal(qd.Result, &results); err != nil {
+		return nil, warnings, fmt.Errorf("decode vector result: %w", err)
+	}
+
+	samples := make([]Sample, 0, len(results))
+	for _, r := range results {
+		sampleTime, val, err := parseSamplePair(r.Value)
+		if err != nil {
+			return nil, warnings, err
+		}
+		samples = append(samples, Sample{Metric: r.Metric, Timestamp: sampleTime, Value: val})
+	}
+	return samples, warnings, nil
+}
+
+// RangeQuery evaluates query over [start, end] at step via
+// /api/v1/query_range.
+func (c *Client) RangeQuery(ctx context.Context, query string, start, end time.Time, step time.Duration) ([]Series, Warnings, error) {
+	params := url.Values{
+		"query": {query},
+		"start": {formatTimestamp(start)},
+		"end":   {formatTimestamp(end)},
+		"step":  {strconv.FormatFloat(step.Seconds(), 'f', -1, 64)},
+	}
+
+	data, warnings, err := c.get(ctx, "/api/v1/query_range", params)
+	if err != nil {
+		return nil, warnings, err
+	}
+
+	var qd queryData
+	if err := json.Unmarshal(data, &qd); err != nil {
+		return nil, warnings, fmt.Errorf("decode range query result: %w", err)
+	}
+	if qd.ResultType != "matrix" {
+		return nil, warnings, fmt.Errorf("unexpected result type %q for range query", qd.ResultType)
+	}
+
+	var results []matrixResult
+	if err := json.Unmarshal(qd.Result, &results); err != nil {
+		return nil, warnings, fmt.Errorf("decode matrix result: %w", err)
+	}
+
+	series := make([]Series, 0, len(results))
+	for _, r := range results {
+		points := make([]analytics.TimeSeriesPoint, 0, len(r.Values))
+		for _, pair := range r.Values {
+			pointTime, val, err := parseSamplePair(pair)
+			if err != nil {
+				return nil, warnings, err
+			}
+			points = append(points, analytics.TimeSeriesPoint{Timestamp: pointTime, Value: val})
+		}
+		series = append(series, Series{Metric: r.Metric, Points: points})
+	}
+	return series, warnings, nil
+}
+
+// SeriesMatching lists the label sets matching matchers (PromQL series
+// selectors) within [start, end], via /api/v1/series.
+func (c *Client) SeriesMatching(ctx context.Context, matchers []string, start, end time.Time) ([]map[string]string, Warnings, error) {
+	params := url.Values{"match[]": matchers}
+	if !start.IsZero() {
+		params.Set("start", formatTimestamp(start))
+	}
+	if !end.IsZero() {
+		params.Set("end", formatTimestamp(end))
+	}
+
+	data, warnings, err := c.get(ctx, "/api/v1/series", params)
+	if err != nil {
+		return nil, warnings, err
+	}
+
+	var sets []map[string]string
+	if err := json.Unmarshal(data, &sets); err != nil {
+		return nil, warnings, fmt.Errorf("decode series result: %w", err)
+	}
+	return sets, warnings, nil
+}
+
+func parseSamplePair(pair [2]interface{}) (time.Time, float64, error) {
+	tsFloat, ok := pair[0].(float64)
+	if !ok {
+		return time.Time{}, 0, fmt.Errorf("unexpected sample timestamp type %T", pair[0])
+	}
+	valStr, ok := pair[1].(string)
+	if !ok {
+		return time.Time{}, 0, fmt.Errorf("unexpected sample value type %T", pair[1])
+	}
+	val, err := strconv.ParseFloat(valStr, 64)
+	if err != nil {
+		return time.Time{}, 0, fmt.Errorf("parse sample value %q: %w", valStr, err)
+	}
+	return timeFromUnixSeconds(tsFloat), val, nil
+}
+
+func timeFromUnixSeconds(s float64) time.Time {
+	sec := int64(s)
+	nsec := int64((s - float64(sec)) * 1e9)
+	return time.Unix(sec, nsec).UTC()
+}
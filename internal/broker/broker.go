@@ -0,0 +1,217 @@
+// Package broker fans out configuration and certificate updates to
+// subscribed Gateway instances over the gRPC Watch API in internal/grpc.
+package broker
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+// ErrSlowConsumer describes a subscriber whose channel filled up before it
+// could drain pending updates. Publish logs it when it disconnects such a
+// subscriber; the subscriber itself only sees its Updates channel close.
+var ErrSlowConsumer = errors.New("broker: subscriber channel full, disconnecting slow consumer")
+
+// subscriberBuffer bounds how many undelivered updates a subscriber may
+// queue before it is considered a slow consumer and dropped.
+const subscriberBuffer = 64
+
+// Update is a single versioned payload fanned out to subscribers of a topic.
+type Update struct {
+	Topic   string
+	Version string
+	Payload interface{}
+}
+
+// Subscription is a live Watch connection for one client.
+type Subscription struct {
+	id      string
+	topic   string
+	updates chan Update
+	acks    chan string
+
+	broker *Broker
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// Updates returns the channel of updates for this subscription. The stream
+// handler should range over it and forward each Update to the client.
+func (s *Subscription) Updates() <-chan Update { return s.updates }
+
+// Ack queues version on the subscription's ack channel, dropping it when that
+// channel is full. Nothing in the broker reads the channel; GetWorkerStatus in
+// internal/grpc reports progress from the acks it records itself.
+func (s *Subscription) Ack(version string) {
+	select {
+	case s.acks <- version:
+	default:
+	}
+}
+
+// Close unregisters the subscription from its Broker.
+func (s *Subscription) Close() {
+	s.broker.unsubscribe(s)
+}
+
+// Broker fans out updates to subscribers of a topic (e.g. "certificates" or
+// "config:waf") using bounded per-client channels, detecting and
+// disconnecting slow consumers rather than blocking publishers.
+type Broker struct {
+	mu          sync.RWMutex
+	subscribers map[string]map[string]*Subscription // topic -> subscriber id -> sub
+	cursors     map[string]string                   // topic -> latest version published
+	history     map[string][]Update                 // topic -> retained updates, for resumable cursors
+
+	historyLimit int
+}
+
+// New creates a Broker that retains up to historyLimit past updates per
+// topic so a reconnecting client can resume from its last-known version
+// instead of replaying everything.
+func New(historyLimit int) *Broker {
+	if historyLimit <= 0 {
+		historyLimit = 32
+	}
+	return &Broker{
+		subscribers:  make(map[string]map[string]*Subscription),
+		cursors:      make(map[string]string),
+		history:      make(map[string][]Update),
+		historyLimit: historyLimit,
+	}
+}
+
+// Subscribe registers a new subscriber for topic and immediately replays
+// retained history to it: every update after resumeFrom if that version is
+// still held, otherwise (resumeFrom empty or no longer retained) the whole
+// retained history.
+func (b *Broker) Subscribe(ctx context.Context, clientID, topic, resumeFrom string) *Subscription {
+	sub := &Subscription{
+		id:      clientID,
+		topic:   topic,
+		updates: make(chan Update, subscriberBuffer),
+		acks:    make(chan string, subscriberBuffer),
+		broker:  b,
+	}
+
+	b.mu.Lock()
+	if b.subscribers[topic] == nil {
+		b.subscribers[topic] = make(map[string]*Subscription)
+	}
+	b.subscribers[topic][clientID] = sub
+
+	backlog := replayFrom(b.history[topic], resumeFrom)
+	b.mu.Unlock()
+
+replay:
+	for _, update := range backlog {
+		if !sub.trySend(update) {
+			log.Printf("broker: %s is behind on replay for %s, disconnecting", clientID, topic)
+			b.unsubscribe(sub)
+			break replay
+		}
+	}
+
+	go func() {
+		<-ctx.Done()
+		b.unsubscribe(sub)
+	}()
+
+	return sub
+}
+
+// replayFrom returns the updates in history that came strictly after
+// resumeFrom, or the whole history if resumeFrom is unknown/empty.
+func replayFrom(history []Update, resumeFrom string) []Update {
+	if resumeFrom == "" {
+		return history
+	}
+	for i, u := range history {
+		if u.Version == resumeFrom {
+			return history[i+1:]
+		}
+	}
+	return history
+}
+
+// unsubscribe removes sub from its topic and closes its updates channel, so a
+// stream handler ranging over Updates() sees the channel close and returns
+// instead of blocking forever once the client disconnects or is dropped as a
+// slow consumer.
+func (b *Broker) unsubscribe(sub *Subscription) {
+	b.mu.Lock()
+	if subs, ok := b.subscribers[sub.topic]; ok {
+		delete(subs, sub.id)
+	}
+	b.mu.Unlock()
+
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	if !sub.closed {
+		sub.closed = true
+		close(sub.updates)
+	}
+}
+
+// trySend delivers update to sub's channel, returning false if the channel
+// is full (the caller should treat sub as a slow consumer) or already closed.
+// Holding sub.mu for the whole send keeps it mutually exclusive with
+// unsubscribe closing the channel, so this never sends on a closed channel.
+func (s *Subscription) trySend(update Update) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return false
+	}
+	select {
+	case s.updates <- update:
+		return true
+	default:
+		return false
+	}
+}
+
+// Publish fans update out to every subscriber of update.Topic. A subscriber
+// whose channel is already full is disconnected rather than blocking the
+// publisher, and a disconnect notice is logged so operators can see which
+// Gateway fell behind.
+func (b *Broker) Publish(update Update) {
+	b.mu.Lock()
+	b.cursors[update.Topic] = update.Version
+	b.history[update.Topic] = append(b.history[update.Topic], update)
+	if len(b.history[update.Topic]) > b.historyLimit {
+		b.history[update.Topic] = b.history[update.Topic][len(b.history[update.Topic])-b.historyLimit:]
+	}
+	subs := make([]*Subscription, 0, len(b.subscribers[update.Topic]))
+	for _, sub := range b.subscribers[update.Topic] {
+		subs = append(subs, sub)
+	}
+	b.mu.Unlock()
+
+	for _, sub := range subs {
+		if !sub.trySend(update) {
+			log.Printf("broker: %v for client %s on topic %s", ErrSlowConsumer, sub.id, update.Topic)
+			b.unsubscribe(sub)
+		}
+	}
+}
+
+// LatestVersion returns the most recently published version for topic.
+func (b *Broker) LatestVersion(topic string) string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.cursors[topic]
+}
+
+// ClientStatus summarizes what a connected client has acknowledged, used by
+// GetWorkerStatus to report per-instance deployment progress.
+type ClientStatus struct {
+	ClientID      string
+	Topic         string
+	LastAckedAt   time.Time
+	LastAckedVers string
+}
@@ -0,0 +1,278 @@
+package certificate
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/acme"
+)
+
+// DirectoryURL returns the ACME directory endpoint for a certificate type.
+func DirectoryURL(t CertificateType) string {
+	if t == CertificateTypeStaging {
+		return "https://acme-staging-v02.api.letsencrypt.org/directory"
+	}
+	return "https://acme-v02.api.letsencrypt.org/directory"
+}
+
+// EABConfig carries the external account binding credentials issued out-of-band
+// by CAs that require it (e.g. some commercial ACME providers).
+type EABConfig struct {
+	KeyID   string
+	HMACKey string // base64url-encoded, as handed out by the CA
+}
+
+// AccountStore persists the ACME account key so registration only happens once
+// per directory, surviving worker restarts.
+type AccountStore interface {
+	LoadAccountKey(ctx context.Context, directoryURL string) (crypto.Signer, error)
+	SaveAccountKey(ctx context.Context, directoryURL string, key crypto.Signer) error
+}
+
+// ChallengeSolver completes a single ACME authorization challenge for a domain.
+// Implementations are registered with NewACMEClient keyed by acme.Challenge.Type
+// ("http-01", "dns-01", "tls-alpn-01").
+type ChallengeSolver interface {
+	ChallengeType() string
+	Present(ctx context.Context, domain, token, keyAuth string) error
+	CleanUp(ctx context.Context, domain, token string) error
+}
+
+// acmeClient is the golang.org/x/crypto/acme backed ACMEClient implementation.
+type acmeClient struct {
+	client  *acme.Client
+	dirURL  string
+	accts   AccountStore
+	solvers map[string]ChallengeSolver
+	eab     *EABConfig
+
+	retryBaseDelay time.Duration
+	retryMaxDelay  time.Duration
+	maxAttempts    int
+}
+
+// NewACMEClient creates an ACMEClient backed by directoryURL. solvers are
+// indexed by ChallengeType (a later solver replaces an earlier one of the same
+// type); for each authorization, the first challenge in the CA's offered order
+// that has a registered solver is used.
+func NewACMEClient(ctx context.Context, directoryURL string, accts AccountStore, solvers []ChallengeSolver, eab *EABConfig) (ACMEClient, error) {
+	key, err := accts.LoadAccountKey(ctx, directoryURL)
+	if err != nil {
+		key, err = generateAccountKey()
+		if err != nil {
+			return nil, fmt.Errorf("generate ACME account key: %w", err)
+		}
+		if err := accts.SaveAccountKey(ctx, directoryURL, key); err != nil {
+			return nil, fmt.Errorf("persist ACME account key: %w", err)
+		}
+	}
+
+	client := &acme.Client{
+		Key:          key,
+		DirectoryURL: directoryURL,
+	}
+
+	bySolverType := make(map[string]ChallengeSolver, len(solvers))
+	for _, s := range solvers {
+		bySolverType[s.ChallengeType()] = s
+	}
+
+	c := &acmeClient{
+		client:         client,
+		dirURL:         directoryURL,
+		accts:          accts,
+		solvers:        bySolverType,
+		eab:            eab,
+		retryBaseDelay: time.Second,
+		retryMaxDelay:  2 * time.Minute,
+		maxAttempts:    5,
+	}
+
+	if err := c.register(ctx); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func generateAccountKey() (crypto.Signer, error) {
+	return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+}
+
+// register creates (or recovers) the ACME account, binding it to the CA via
+// external account binding when configured.
+func (c *acmeClient) register(ctx context.Context) error {
+	acct := &acme.Account{}
+	if c.eab != nil {
+		binding, err := acme.ExternalAccountBinding(c.client.Key, c.eab.KeyID, []byte(c.eab.HMACKey), c.dirURL)
+		if err != nil {
+			return fmt.Errorf("build external account binding: %w", err)
+		}
+		acct.ExternalAccountBinding = binding
+	}
+
+	_, err := c.client.Register(ctx, acct, acme.AcceptTOS)
+	if err != nil && !strings.Contains(err.Error(), "account already exists") {
+		return fmt.Errorf("ACME account registration: %w", err)
+	}
+	return nil
+}
+
+// RenewCertificate requests a new certificate for domain through a full
+// order -> authorize -> finalize ACME flow.
+func (c *acmeClient) RenewCertificate(ctx context.Context, domain string) (*Certificate, error) {
+	order, err := c.client.AuthorizeOrder(ctx, acme.DomainIDs(domain))
+	if err != nil {
+		return nil, fmt.Errorf("create order for %s: %w", domain, err)
+	}
+
+	for _, authzURL := range order.AuthzURLs {
+		if err := c.completeAuthorization(ctx, authzURL); err != nil {
+			return nil, fmt.Errorf("authorize %s: %w", domain, err)
+		}
+	}
+
+	order, err = c.client.WaitOrder(ctx, order.URI)
+	if err != nil {
+		return nil, fmt.Errorf("wait for order ready: %w", err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("generate certificate key: %w", err)
+	}
+
+	csr, err := newCSR(key, domain)
+	if err != nil {
+		return nil, fmt.Errorf("build CSR for %s: %w", domain, err)
+	}
+
+	der, _, err := c.client.CreateOrderCert(ctx, order.FinalizeURL, csr, true)
+	if err != nil {
+		return nil, fmt.Errorf("finalize order for %s: %w", domain, err)
+	}
+
+	keyBytes, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("marshal certificate key: %w", err)
+	}
+
+	leaf, err := x509.ParseCertificate(der[0])
+	if err != nil {
+		return nil, fmt.Errorf("parse issued certificate: %w", err)
+	}
+
+	return &Certificate{
+		Domain:      domain,
+		CertData:    encodeCertChain(der),
+		PrivateKey:  keyBytes,
+		Expiry:      leaf.NotAfter,
+		Created:     time.Now(),
+		LastUpdated: time.Now(),
+	}, nil
+}
+
+// ValidateCertificate checks that the certificate is well formed and not expired.
+func (c *acmeClient) ValidateCertificate(ctx context.Context, cert *Certificate) error {
+	if len(cert.CertData) == 0 {
+		return fmt.Errorf("certificate for %s has no data", cert.Domain)
+	}
+	leaf, err := x509.ParseCertificate(cert.CertData)
+	if err != nil {
+		// CertData may be a full chain; fall back to decoding just the leaf.
+		certs, chainErr := x509.ParseCertificates(cert.CertData)
+		if chainErr != nil || len(certs) == 0 {
+			return fmt.Errorf("parse certificate for %s: %w", cert.Domain, err)
+		}
+		leaf = certs[0]
+	}
+	if time.Now().After(leaf.NotAfter) {
+		return fmt.Errorf("certificate for %s expired at %s", cert.Domain, leaf.NotAfter)
+	}
+	return nil
+}
+
+// completeAuthorization drives a single authorization through challenge
+// selection, presentation, and CA-side verification.
+func (c *acmeClient) completeAuthorization(ctx context.Context, authzURL string) error {
+	authz, err := c.client.GetAuthorization(ctx, authzURL)
+	if err != nil {
+		return err
+	}
+	if authz.Status == acme.StatusValid {
+		return nil
+	}
+
+	domain := authz.Identifier.Value
+
+	var chal *acme.Challenge
+	var solver ChallengeSolver
+	for _, candidate := range authz.Challenges {
+		if s, ok := c.solvers[candidate.Type]; ok {
+			chal, solver = candidate, s
+			break
+		}
+	}
+	if chal == nil {
+		return fmt.Errorf("no solver registered for any offered challenge on %s", domain)
+	}
+
+	keyAuth, err := c.client.HTTP01ChallengeResponse(chal.Token)
+	if err != nil {
+		return fmt.Errorf("compute key authorization: %w", err)
+	}
+
+	if err := solver.Present(ctx, domain, chal.Token, keyAuth); err != nil {
+		return fmt.Errorf("present %s challenge: %w", chal.Type, err)
+	}
+	defer solver.CleanUp(ctx, domain, chal.Token)
+
+	if _, err := c.client.Accept(ctx, chal); err != nil {
+		return fmt.Errorf("accept %s challenge: %w", chal.Type, err)
+	}
+
+	if _, err := c.client.WaitAuthorization(ctx, authzURL); err != nil {
+		return fmt.Errorf("wait for authorization: %w", err)
+	}
+
+	return nil
+}
+
+func encodeCertChain(der [][]byte) []byte {
+	out := make([]byte, 0)
+	for _, block := range der {
+		out = append(out, block...)
+	}
+	return out
+}
+
+func newCSR(key *ecdsa.PrivateKey, domain string) ([]byte, error) {
+	template := &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: domain},
+		DNSNames: []string{domain},
+	}
+	return x509.CreateCertificateRequest(rand.Reader, template, key)
+}
+
+// backoffDelay returns the exponential backoff delay for the given attempt,
+// capped at retryMaxDelay before jitter is added; the jitter (up to a quarter
+// of the capped delay) can take the result past retryMaxDelay.
+func (c *acmeClient) backoffDelay(attempt int, jitter func(n int64) int64) time.Duration {
+	delay := c.retryBaseDelay * time.Duration(1<<uint(attempt))
+	if delay > c.retryMaxDelay {
+		delay = c.retryMaxDelay
+	}
+	if jitter != nil {
+		delay += time.Duration(jitter(int64(delay) / 4))
+	}
+	return delay
+}
+
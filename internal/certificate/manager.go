@@ -4,15 +4,64 @@ package certificate
 
 import (
 	"context"
+	"math/rand"
 	"time"
+
+	"github.com/awesomeapibrasil/gateway-worker/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// renewalJitter bounds the random delay checkAndRenewCertificates waits before
+// each due renewal. The delays run one after another, so a check cycle with n
+// due certificates spreads them over up to n*renewalJitter, and a CA doesn't
+// see every due certificate at once.
+const renewalJitter = 10 * time.Minute
+
 // Manager handles certificate lifecycle management
 type Manager struct {
 	acmeClient   ACMEClient
 	storage      Storage
 	distributor  Distributor
 	validator    Validator
+
+	onDemand    *onDemandState // non-nil once EnableOnDemandTLS is called
+	ocspStapler *OCSPStapler   // non-nil once a stapler is attached
+	publisher   VersionPublisher
+}
+
+// VersionPublisher notifies connected Gateway instances over the Watch gRPC
+// API that a new certificate version is available. Defined locally, rather
+// than importing internal/grpc, so this package stays decoupled from the
+// gRPC transport layer - the same pattern internal/queue's Notifier uses to
+// avoid importing internal/integration.
+type VersionPublisher interface {
+	PublishCertificateUpdate(domain, version string, certData []byte)
+}
+
+// SetPublisher attaches the gRPC service that streams certificate updates to
+// watching Gateway instances. Unset by default, in which case stored
+// certificates are never broadcast.
+func (m *Manager) SetPublisher(publisher VersionPublisher) {
+	m.publisher = publisher
+}
+
+// publishUpdate notifies the publisher, if one is attached, that cert was
+// just stored. Certificates have no version field of their own, so
+// LastUpdated (set whenever a certificate is (re)issued) doubles as the
+// version Gateway instances report back in WatchRequest.KnownVersions.
+func (m *Manager) publishUpdate(cert *Certificate) {
+	if m.publisher == nil {
+		return
+	}
+	m.publisher.PublishCertificateUpdate(cert.Domain, cert.LastUpdated.UTC().Format(time.RFC3339Nano), cert.CertData)
+}
+
+// SetOCSPStapler attaches a background OCSP stapler; staples it keeps fresh
+// are attached to certificates returned from GetCertificate.
+func (m *Manager) SetOCSPStapler(stapler *OCSPStapler) {
+	m.ocspStapler = stapler
 }
 
 // ACMEClient interface for ACME protocol operations (Let's Encrypt, etc.)
@@ -97,21 +146,35 @@ func (m *Manager) MonitorCertificates(ctx context.Context) error {
 	}
 }
 
-// checkAndRenewCertificates checks all certificates for expiration and renews if needed
+// checkAndRenewCertificates checks all certificates for expiration and renews if needed.
+// Due renewals are batched with jitter so a fleet of expiring certificates
+// doesn't all hit the CA in the same instant (thundering herd).
 func (m *Manager) checkAndRenewCertificates(ctx context.Context) error {
 	certificates, err := m.storage.List(ctx)
 	if err != nil {
 		return err
 	}
 
+	var due []*Certificate
 	for _, cert := range certificates {
 		// Check if certificate expires within 30 days
 		if time.Until(cert.Expiry) <= 30*24*time.Hour {
-			if err := m.renewCertificate(ctx, cert.Domain); err != nil {
-				// If renewal fails, deploy temporary certificate
-				if err := m.deployTemporaryCertificate(ctx, cert.Domain, "renewal_failed"); err != nil {
-					// Log error for temporary certificate deployment failure
-				}
+			due = append(due, cert)
+		}
+	}
+
+	for _, cert := range due {
+		jitter := time.Duration(rand.Int63n(int64(renewalJitter)))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(jitter):
+		}
+
+		if err := m.renewCertificate(ctx, cert.Domain); err != nil {
+			// If renewal fails, deploy temporary certificate
+			if err := m.deployTemporaryCertificate(ctx, cert.Domain, "renewal_failed"); err != nil {
+				// Log error for temporary certificate deployment failure
 			}
 		}
 	}
@@ -119,28 +182,69 @@ func (m *Manager) checkAndRenewCertificates(ctx context.Context) error {
 	return nil
 }
 
-// renewCertificate renews a certificate using ACME protocol
+// renewCertificate renews a certificate using the ACME protocol, retrying
+// with exponential backoff before falling back to a temporary certificate.
 func (m *Manager) renewCertificate(ctx context.Context, domain string) error {
-	// Renew certificate
-	newCert, err := m.acmeClient.RenewCertificate(ctx, domain)
-	if err != nil {
-		return err
-	}
+	ctx, span := tracing.Tracer().Start(ctx, "certificate.renew", trace.WithAttributes(
+		attribute.String("certificate.domain", domain),
+	))
+	defer span.End()
 
-	// Validate new certificate
-	if err := m.validator.ValidateCertificate(ctx, newCert); err != nil {
-		return err
-	}
+	const maxAttempts = 5
+	const baseDelay = 2 * time.Second
 
-	// Store new certificate
-	if err := m.storage.Store(ctx, newCert); err != nil {
+	var lastErr error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		if attempt > 0 {
+			delay := baseDelay * time.Duration(1<<uint(attempt-1))
+			jitter := time.Duration(rand.Int63n(int64(delay) / 2))
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(delay + jitter):
+			}
+		}
+
+		newCert, err := m.acmeClient.RenewCertificate(ctx, domain)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		validateCtx, validateSpan := tracing.Tracer().Start(ctx, "certificate.validate")
+		err = m.validator.ValidateCertificate(validateCtx, newCert)
+		validateSpan.End()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		storeCtx, storeSpan := tracing.Tracer().Start(ctx, "certificate.store")
+		err = m.storage.Store(storeCtx, newCert)
+		storeSpan.End()
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return err
+		}
+		m.publishUpdate(newCert)
+
+		// Deploy to Gateway instances
+		// TODO: Get Gateway instances from configuration
+		instances := []string{} // Placeholder
+		distributeCtx, distributeSpan := tracing.Tracer().Start(ctx, "certificate.distribute")
+		err = m.distributor.Deploy(distributeCtx, newCert, instances)
+		distributeSpan.End()
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
 		return err
 	}
 
-	// Deploy to Gateway instances
-	// TODO: Get Gateway instances from configuration
-	instances := []string{} // Placeholder
-	return m.distributor.Deploy(ctx, newCert, instances)
+	span.RecordError(lastErr)
+	span.SetStatus(codes.Error, lastErr.Error())
+	return lastErr
 }
 
 // deployTemporaryCertificate creates and deploys a temporary certificate
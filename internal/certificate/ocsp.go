@@ -0,0 +1,181 @@
+package certificate
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+)
+
+// ocspRefreshBefore is how long before NextUpdate the stapler tries to
+// refresh a response, giving it several attempts before the staple goes stale.
+const ocspRefreshBefore = 12 * time.Hour
+
+// ocspNearExpiryGrace is how close to NextUpdate a failed fetch is tolerated
+// before the stapler stops serving the stale staple altogether.
+const ocspNearExpiryGrace = time.Hour
+
+// OCSPStapler periodically fetches and caches OCSP responses for the
+// certificates it is told about, so GetCertificate can attach a staple
+// without blocking on the responder at handshake time.
+type OCSPStapler struct {
+	httpClient *http.Client
+
+	mu       sync.RWMutex
+	entries  map[string]*ocspEntry // domain -> cached response
+	interval time.Duration
+}
+
+type ocspEntry struct {
+	raw        []byte
+	nextUpdate time.Time
+	cert       *Certificate
+	issuer     *x509.Certificate
+}
+
+// NewOCSPStapler creates a stapler that re-checks cached responses every
+// interval (a few minutes is typical; each entry only actually refetches
+// once it's within ocspRefreshBefore of its NextUpdate).
+func NewOCSPStapler(interval time.Duration) *OCSPStapler {
+	return &OCSPStapler{
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		entries:    make(map[string]*ocspEntry),
+		interval:   interval,
+	}
+}
+
+// Track registers domain for background OCSP refresh. issuer is the CA
+// certificate that signed cert, required to build the OCSP request.
+func (s *OCSPStapler) Track(domain string, cert *Certificate, issuer *x509.Certificate) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[domain] = &ocspEntry{cert: cert, issuer: issuer}
+}
+
+// staple returns the cached OCSP response for domain, if one is fresh
+// enough to serve.
+func (s *OCSPStapler) staple(domain string) ([]byte, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entry, ok := s.entries[domain]
+	if !ok || entry.raw == nil {
+		return nil, false
+	}
+	if time.Now().After(entry.nextUpdate) {
+		return nil, false
+	}
+	return entry.raw, true
+}
+
+// Run loops until ctx is cancelled, refreshing any tracked entry whose
+// staple is missing or within ocspRefreshBefore of expiry.
+func (s *OCSPStapler) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			s.refreshDue(ctx)
+		}
+	}
+}
+
+func (s *OCSPStapler) refreshDue(ctx context.Context) {
+	s.mu.RLock()
+	domains := make([]string, 0, len(s.entries))
+	for domain, entry := range s.entries {
+		if entry.raw == nil || time.Until(entry.nextUpdate) <= ocspRefreshBefore {
+			domains = append(domains, domain)
+		}
+	}
+	s.mu.RUnlock()
+
+	for _, domain := range domains {
+		if err := s.refresh(ctx, domain); err != nil {
+			log.Printf("ocsp: refresh failed for %s: %v", domain, err)
+		}
+	}
+}
+
+func (s *OCSPStapler) refresh(ctx context.Context, domain string) error {
+	s.mu.RLock()
+	entry, ok := s.entries[domain]
+	s.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+
+	leaf, err := x509.ParseCertificate(entry.cert.CertData)
+	if err != nil {
+		return fmt.Errorf("parse leaf for %s: %w", domain, err)
+	}
+	if len(leaf.OCSPServer) == 0 {
+		return fmt.Errorf("no OCSP responder advertised for %s", domain)
+	}
+
+	raw, nextUpdate, err := s.fetch(ctx, leaf, entry.issuer, leaf.OCSPServer[0])
+	if err != nil {
+		s.mu.RLock()
+		nearExpiry := entry.raw != nil && time.Until(entry.nextUpdate) <= ocspNearExpiryGrace
+		s.mu.RUnlock()
+
+		if nearExpiry {
+			log.Printf("ocsp: %s staple near expiry and refresh failed, serving without staple: %v", domain, err)
+			s.mu.Lock()
+			entry.raw = nil
+			s.mu.Unlock()
+		}
+		return err
+	}
+
+	s.mu.Lock()
+	entry.raw = raw
+	entry.nextUpdate = nextUpdate
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *OCSPStapler) fetch(ctx context.Context, leaf, issuer *x509.Certificate, responderURL string) ([]byte, time.Time, error) {
+	req, err := ocsp.CreateRequest(leaf, issuer, nil)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("build OCSP request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, responderURL, bytes.NewReader(req))
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	httpReq.Header.Set("Content-Type", "application/ocsp-request")
+
+	resp, err := s.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	parsed, err := ocsp.ParseResponseForCert(body, leaf, issuer)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("parse OCSP response: %w", err)
+	}
+	if parsed.Status != ocsp.Good {
+		return nil, time.Time{}, fmt.Errorf("OCSP responder reported status %d for %s", parsed.Status, leaf.Subject.CommonName)
+	}
+
+	return body, parsed.NextUpdate, nil
+}
@@ -0,0 +1,184 @@
+package certificate
+
+import (
+	"container/list"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"sync"
+)
+
+// DecisionFunc lets operators restrict which hostnames are allowed to
+// trigger on-demand issuance, e.g. checking against a known-routes list.
+// Returning a non-nil error refuses the handshake for that name.
+type DecisionFunc func(name string) error
+
+// EnableOnDemandTLS wires GetCertificate to issue certificates just-in-time
+// for SNI names approved by decide. cacheSize bounds the in-memory LRU tier;
+// everything else is served from (and written back to) m.storage.
+func (m *Manager) EnableOnDemandTLS(decide DecisionFunc, cacheSize int) {
+	m.onDemand = &onDemandState{
+		decide: decide,
+		cache:  newCertCache(cacheSize),
+	}
+}
+
+// onDemandState holds the extra bookkeeping GetCertificate needs: an
+// in-memory cache and a set of per-domain locks so concurrent handshakes for
+// the same SNI name coalesce into a single ACME order.
+type onDemandState struct {
+	decide DecisionFunc
+	cache  *certCache
+
+	obtainLocks sync.Map // domain -> *sync.Mutex
+}
+
+// GetCertificate is a tls.Config.GetCertificate-compatible entrypoint: it
+// serves from the in-memory cache, falling back to storage, and finally to
+// on-demand ACME issuance gated by the configured DecisionFunc.
+func (m *Manager) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	if m.onDemand == nil {
+		return nil, fmt.Errorf("on-demand TLS is not enabled")
+	}
+
+	name := hello.ServerName
+	if name == "" {
+		return nil, fmt.Errorf("client did not send SNI")
+	}
+
+	if cert, ok := m.onDemand.cache.get(name); ok {
+		return m.attachStaple(name, cert), nil
+	}
+
+	ctx := context.Background()
+	if stored, err := m.storage.Retrieve(ctx, name); err == nil && stored != nil {
+		cert, err := toTLSCertificate(stored)
+		if err != nil {
+			return nil, err
+		}
+		m.onDemand.cache.put(name, cert)
+		return m.attachStaple(name, cert), nil
+	}
+
+	if m.onDemand.decide != nil {
+		if err := m.onDemand.decide(name); err != nil {
+			return nil, fmt.Errorf("on-demand issuance refused for %s: %w", name, err)
+		}
+	}
+
+	return m.obtainOnDemand(ctx, name)
+}
+
+// obtainOnDemand coalesces concurrent handshakes for the same domain into a
+// single ACME order via a per-domain lock.
+func (m *Manager) obtainOnDemand(ctx context.Context, domain string) (*tls.Certificate, error) {
+	lockIface, _ := m.onDemand.obtainLocks.LoadOrStore(domain, &sync.Mutex{})
+	lock := lockIface.(*sync.Mutex)
+	lock.Lock()
+	defer lock.Unlock()
+
+	// Another goroutine may have just populated the cache while we waited.
+	if cert, ok := m.onDemand.cache.get(domain); ok {
+		return m.attachStaple(domain, cert), nil
+	}
+
+	newCert, err := m.acmeClient.RenewCertificate(ctx, domain)
+	if err != nil {
+		return nil, fmt.Errorf("on-demand issuance for %s: %w", domain, err)
+	}
+	if err := m.validator.ValidateCertificate(ctx, newCert); err != nil {
+		return nil, err
+	}
+	if err := m.storage.Store(ctx, newCert); err != nil {
+		return nil, err
+	}
+	m.publishUpdate(newCert)
+
+	cert, err := toTLSCertificate(newCert)
+	if err != nil {
+		return nil, err
+	}
+	m.onDemand.cache.put(domain, cert)
+
+	return m.attachStaple(domain, cert), nil
+}
+
+func (m *Manager) attachStaple(domain string, cert *tls.Certificate) *tls.Certificate {
+	if m.ocspStapler == nil {
+		return cert
+	}
+	if staple, ok := m.ocspStapler.staple(domain); ok {
+		stapled := *cert
+		stapled.OCSPStaple = staple
+		return &stapled
+	}
+	return cert
+}
+
+func toTLSCertificate(cert *Certificate) (*tls.Certificate, error) {
+	tlsCert, err := tls.X509KeyPair(cert.CertData, cert.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("parse keypair for %s: %w", cert.Domain, err)
+	}
+	return &tlsCert, nil
+}
+
+// certCache is a small LRU keyed by SNI hostname, used as the fast tier in
+// front of the Storage-backed lookup.
+type certCache struct {
+	mu       sync.Mutex
+	capacity int
+	ll       *list.List
+	items    map[string]*list.Element
+}
+
+type certCacheEntry struct {
+	key  string
+	cert *tls.Certificate
+}
+
+func newCertCache(capacity int) *certCache {
+	if capacity <= 0 {
+		capacity = 1024
+	}
+	return &certCache{
+		capacity: capacity,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+func (c *certCache) get(key string) (*tls.Certificate, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	c.ll.MoveToFront(el)
+	return el.Value.(*certCacheEntry).cert, true
+}
+
+func (c *certCache) put(key string, cert *tls.Certificate) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		el.Value.(*certCacheEntry).cert = cert
+		c.ll.MoveToFront(el)
+		return
+	}
+
+	el := c.ll.PushFront(&certCacheEntry{key: key, cert: cert})
+	c.items[key] = el
+
+	for c.ll.Len() > c.capacity {
+		oldest := c.ll.Back()
+		if oldest == nil {
+			break
+		}
+		c.ll.Remove(oldest)
+		delete(c.items, oldest.Value.(*certCacheEntry).key)
+	}
+}
@@ -0,0 +1,162 @@
+package certificate
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const http01ChallengePath = "/.well-known/acme-challenge/"
+
+// HTTP01Solver completes http-01 challenges by serving the key authorization
+// under the `/.well-known/acme-challenge/` path from Handler. Neither main.go
+// nor the health HTTP server mounts Handler.
+type HTTP01Solver struct {
+	mu        sync.RWMutex
+	keyAuths  map[string]string // token -> key authorization
+}
+
+// NewHTTP01Solver creates an http-01 solver. Register its Handler with the
+// health HTTP server so incoming validation requests reach it.
+func NewHTTP01Solver() *HTTP01Solver {
+	return &HTTP01Solver{keyAuths: make(map[string]string)}
+}
+
+func (s *HTTP01Solver) ChallengeType() string { return "http-01" }
+
+func (s *HTTP01Solver) Present(ctx context.Context, domain, token, keyAuth string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keyAuths[token] = keyAuth
+	return nil
+}
+
+func (s *HTTP01Solver) CleanUp(ctx context.Context, domain, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.keyAuths, token)
+	return nil
+}
+
+// Handler serves http-01 validation requests. Mount it at http01ChallengePath.
+func (s *HTTP01Solver) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.URL.Path, http01ChallengePath)
+
+		s.mu.RLock()
+		keyAuth, ok := s.keyAuths[token]
+		s.mu.RUnlock()
+
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte(keyAuth))
+	})
+}
+
+// TLSALPN01Solver completes tls-alpn-01 challenges by serving a self-signed
+// certificate carrying the acmeValidation extension over the ALPN protocol
+// "acme-tls/1". Gateway instances (or this worker, if it terminates TLS for
+// validation) should route that ALPN to GetCertificate.
+type TLSALPN01Solver struct {
+	mu    sync.RWMutex
+	certs map[string]*tls.Certificate // domain -> validation certificate
+}
+
+func NewTLSALPN01Solver() *TLSALPN01Solver {
+	return &TLSALPN01Solver{certs: make(map[string]*tls.Certificate)}
+}
+
+func (s *TLSALPN01Solver) ChallengeType() string { return "tls-alpn-01" }
+
+func (s *TLSALPN01Solver) Present(ctx context.Context, domain, token, keyAuth string) error {
+	cert, err := tlsALPN01Certificate(domain, keyAuth)
+	if err != nil {
+		return fmt.Errorf("build tls-alpn-01 certificate for %s: %w", domain, err)
+	}
+	s.mu.Lock()
+	s.certs[domain] = cert
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *TLSALPN01Solver) CleanUp(ctx context.Context, domain, token string) error {
+	s.mu.Lock()
+	delete(s.certs, domain)
+	s.mu.Unlock()
+	return nil
+}
+
+// CertificateFor returns the validation certificate for domain, if any is
+// currently presented. Wire this into GetConfigForClient when negotiating
+// the "acme-tls/1" ALPN protocol.
+func (s *TLSALPN01Solver) CertificateFor(domain string) (*tls.Certificate, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	cert, ok := s.certs[domain]
+	return cert, ok
+}
+
+// DNSProvider creates/removes the `_acme-challenge` TXT record for a domain.
+type DNSProvider interface {
+	AddTXTRecord(ctx context.Context, fqdn, value string) error
+	RemoveTXTRecord(ctx context.Context, fqdn, value string) error
+}
+
+// DNS01Solver completes dns-01 challenges via a pluggable DNSProvider
+// (e.g. Route53, Cloudflare), selected by the caller based on config.
+type DNS01Solver struct {
+	provider        DNSProvider
+	propagationWait func(domain string)
+
+	mu      sync.RWMutex
+	records map[string]string // domain+"/"+token -> TXT record value, for CleanUp
+}
+
+func NewDNS01Solver(provider DNSProvider) *DNS01Solver {
+	return &DNS01Solver{provider: provider, records: make(map[string]string)}
+}
+
+func (s *DNS01Solver) ChallengeType() string { return "dns-01" }
+
+func (s *DNS01Solver) Present(ctx context.Context, domain, token, keyAuth string) error {
+	value := sha256Base64URL(keyAuth)
+	fqdn := "_acme-challenge." + domain
+	if err := s.provider.AddTXTRecord(ctx, fqdn, value); err != nil {
+		return fmt.Errorf("create TXT record %s: %w", fqdn, err)
+	}
+
+	s.mu.Lock()
+	s.records[domain+"/"+token] = value
+	s.mu.Unlock()
+
+	if s.propagationWait != nil {
+		s.propagationWait(domain)
+	}
+	return nil
+}
+
+func (s *DNS01Solver) CleanUp(ctx context.Context, domain, token string) error {
+	key := domain + "/" + token
+	s.mu.RLock()
+	value, ok := s.records[key]
+	s.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+
+	fqdn := "_acme-challenge." + domain
+	if err := s.provider.RemoveTXTRecord(ctx, fqdn, value); err != nil {
+		return fmt.Errorf("remove TXT record %s: %w", fqdn, err)
+	}
+
+	s.mu.Lock()
+	delete(s.records, key)
+	s.mu.Unlock()
+	return nil
+}
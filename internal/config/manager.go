@@ -6,6 +6,11 @@ import (
 	"context"
 	"encoding/json"
 	"time"
+
+	"github.com/awesomeapibrasil/gateway-worker/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Manager handles configuration lifecycle management
@@ -13,6 +18,32 @@ type Manager struct {
 	storage    Storage
 	validator  Validator
 	distributor Distributor
+	publisher  VersionPublisher
+}
+
+// VersionPublisher notifies connected Gateway instances over the Watch gRPC
+// API that a new configuration version is available. Defined locally, rather
+// than importing internal/grpc, so this package stays decoupled from the
+// gRPC transport layer - the same pattern internal/queue's Notifier uses to
+// avoid importing internal/integration.
+type VersionPublisher interface {
+	PublishConfigUpdate(configType, version string, data []byte)
+}
+
+// SetPublisher attaches the gRPC service that streams configuration updates
+// to watching Gateway instances. Unset by default, in which case stored
+// configuration is never broadcast.
+func (m *Manager) SetPublisher(publisher VersionPublisher) {
+	m.publisher = publisher
+}
+
+// publishUpdate notifies the publisher, if one is attached, that config was
+// just stored.
+func (m *Manager) publishUpdate(config *Configuration) {
+	if m.publisher == nil {
+		return
+	}
+	m.publisher.PublishConfigUpdate(string(config.Type), config.Version, config.Data)
 }
 
 // Storage interface for configuration persistence
@@ -202,16 +233,30 @@ func New(storage Storage, validator Validator, distributor Distributor) *Manager
 
 // UpdateWAFRules updates WAF rules with validation and deployment
 func (m *Manager) UpdateWAFRules(ctx context.Context, rules []WAFRule, emergencyDeployment bool) error {
+	ctx, span := tracing.Tracer().Start(ctx, "config.update_waf_rules", trace.WithAttributes(
+		attribute.Int("waf.rule_count", len(rules)),
+		attribute.Bool("waf.emergency_deployment", emergencyDeployment),
+	))
+	defer span.End()
+
 	// Validate rules
 	if !emergencyDeployment {
-		if errors := m.validator.ValidateWAFRules(ctx, rules); len(errors) > 0 {
-			return NewValidationError(errors)
+		_, validateSpan := tracing.Tracer().Start(ctx, "config.validate")
+		errs := m.validator.ValidateWAFRules(ctx, rules)
+		validateSpan.End()
+		if len(errs) > 0 {
+			err := NewValidationError(errs)
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return err
 		}
 	}
 
 	// Create configuration
 	rulesData, err := json.Marshal(rules)
 	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		return err
 	}
 
@@ -225,14 +270,27 @@ func (m *Manager) UpdateWAFRules(ctx context.Context, rules []WAFRule, emergency
 	}
 
 	// Store configuration
-	if err := m.storage.Store(ctx, config); err != nil {
+	storeCtx, storeSpan := tracing.Tracer().Start(ctx, "config.store")
+	err = m.storage.Store(storeCtx, config)
+	storeSpan.End()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		return err
 	}
+	m.publishUpdate(config)
 
 	// Deploy to Gateway instances
 	// TODO: Get Gateway instances from configuration
 	instances := []string{} // Placeholder
-	return m.distributor.DeployWAFRules(ctx, rules, instances)
+	distributeCtx, distributeSpan := tracing.Tracer().Start(ctx, "config.distribute")
+	err = m.distributor.DeployWAFRules(distributeCtx, rules, instances)
+	distributeSpan.End()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
 }
 
 // UpdateRoutingConfig updates routing configuration
@@ -261,6 +319,7 @@ func (m *Manager) UpdateRoutingConfig(ctx context.Context, routingConfig Routing
 	if err := m.storage.Store(ctx, config); err != nil {
 		return err
 	}
+	m.publishUpdate(config)
 
 	// Deploy to Gateway instances
 	instances := []string{} // Placeholder
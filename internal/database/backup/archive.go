@@ -0,0 +1,218 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/awesomeapibrasil/gateway-worker/internal/database"
+)
+
+// ArchiveSource is how ArchiveService moves cold data out of a live table.
+// Unlike TableSource (used for point-in-time backup snapshots), archiving
+// actually removes the archived rows from the source once they're safely
+// written, so the hot table shrinks.
+type ArchiveSource interface {
+	ReadOlderThan(ctx context.Context, config database.ArchivalConfig) (r io.Reader, recordCount int64, err error)
+	DeleteOlderThan(ctx context.Context, config database.ArchivalConfig) error
+	WriteRestored(ctx context.Context, table string, r io.Reader) error
+}
+
+// archiveManifest is the per-archive sidecar file; archivePointer is a
+// small lookup object keyed only by archive ID, so RestoreArchivedData
+// (which only receives an ID) can find the table and manifest without
+// scanning every table's archives.
+type archiveManifest struct {
+	ID          string    `json:"id"`
+	Table       string    `json:"table"`
+	Key         string    `json:"key"`
+	RecordCount int64     `json:"record_count"`
+	Size        int64     `json:"size"`
+	Checksum    string    `json:"checksum"`
+	Compressed  bool      `json:"compressed"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type archivePointer struct {
+	Table       string `json:"table"`
+	ManifestKey string `json:"manifest_key"`
+}
+
+// ArchiveService implements database.Archiver against a pluggable Target,
+// using the same gzip/zstd Compressor and checksum machinery as Service.
+type ArchiveService struct {
+	target Target
+	source ArchiveSource
+}
+
+// NewArchiveService builds an ArchiveService.
+func NewArchiveService(target Target, source ArchiveSource) *ArchiveService {
+	return &ArchiveService{target: target, source: source}
+}
+
+// ArchiveOldData reads every row matching config from the live table,
+// writes it (compressed, with a manifest) to the Target, then deletes it
+// from the source once the write has succeeded.
+func (a *ArchiveService) ArchiveOldData(ctx context.Context, config database.ArchivalConfig) (*database.ArchivalResult, error) {
+	data, recordCount, err := a.source.ReadOlderThan(ctx, config)
+	if err != nil {
+		return nil, fmt.Errorf("archive: read rows for %s: %w", config.Table, err)
+	}
+
+	id := generateID("archive")
+	var compressor Compressor = noopCompressor{}
+	if config.Compress {
+		compressor = gzipCompressor{}
+	}
+
+	key := fmt.Sprintf("archive/%s/%s%s", config.Table, id, compressor.Ext())
+	pr, pw := io.Pipe()
+	go func() {
+		cw, err := compressor.NewWriter(pw)
+		if err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+		_, copyErr := io.Copy(cw, data)
+		closeErr := cw.Close()
+		if copyErr != nil {
+			pw.CloseWithError(copyErr)
+			return
+		}
+		pw.CloseWithError(closeErr)
+	}()
+
+	hasher := sha256.New()
+	size, err := a.target.Write(ctx, key, io.TeeReader(pr, hasher))
+	if err != nil {
+		return nil, fmt.Errorf("archive: write rows for %s: %w", config.Table, err)
+	}
+
+	manifest := archiveManifest{
+		ID:          id,
+		Table:       config.Table,
+		Key:         key,
+		RecordCount: recordCount,
+		Size:        size,
+		Checksum:    hex.EncodeToString(hasher.Sum(nil)),
+		Compressed:  config.Compress,
+		CreatedAt:   time.Now(),
+	}
+
+	manifestKey := archiveManifestKey(config.Table, id)
+	if err := a.writeJSON(ctx, manifestKey, manifest); err != nil {
+		return nil, fmt.Errorf("archive: write manifest for %s: %w", config.Table, err)
+	}
+	if err := a.writeJSON(ctx, archivePointerKey(id), archivePointer{Table: config.Table, ManifestKey: manifestKey}); err != nil {
+		return nil, fmt.Errorf("archive: write pointer for %s: %w", id, err)
+	}
+
+	if err := a.source.DeleteOlderThan(ctx, config); err != nil {
+		return nil, fmt.Errorf("archive: delete archived rows for %s: %w", config.Table, err)
+	}
+
+	return &database.ArchivalResult{
+		ArchiveID:       id,
+		Table:           config.Table,
+		RecordsArchived: recordCount,
+		CompressedSize:  size,
+		CreatedAt:       manifest.CreatedAt,
+	}, nil
+}
+
+// RestoreArchivedData restores archiveID back into its source table,
+// verifying the stored checksum before returning success.
+func (a *ArchiveService) RestoreArchivedData(ctx context.Context, archiveID string) error {
+	var pointer archivePointer
+	if err := a.readJSON(ctx, archivePointerKey(archiveID), &pointer); err != nil {
+		return fmt.Errorf("archive: find archive %s: %w", archiveID, err)
+	}
+
+	var manifest archiveManifest
+	if err := a.readJSON(ctx, pointer.ManifestKey, &manifest); err != nil {
+		return fmt.Errorf("archive: load manifest for %s: %w", archiveID, err)
+	}
+
+	rc, err := a.target.Open(ctx, manifest.Key)
+	if err != nil {
+		return fmt.Errorf("archive: open %s: %w", archiveID, err)
+	}
+	defer rc.Close()
+
+	hasher := sha256.New()
+	dr, err := compressorForExt(filepath.Ext(manifest.Key)).NewReader(io.TeeReader(rc, hasher))
+	if err != nil {
+		return fmt.Errorf("archive: decompress %s: %w", archiveID, err)
+	}
+	defer dr.Close()
+
+	if err := a.source.WriteRestored(ctx, manifest.Table, dr); err != nil {
+		return fmt.Errorf("archive: restore %s: %w", archiveID, err)
+	}
+
+	if checksum := hex.EncodeToString(hasher.Sum(nil)); checksum != manifest.Checksum {
+		return fmt.Errorf("archive: %s failed checksum verification (manifest %s, got %s)", archiveID, manifest.Checksum, checksum)
+	}
+	return nil
+}
+
+// ListArchives returns every archive recorded for table.
+func (a *ArchiveService) ListArchives(ctx context.Context, table string) ([]database.ArchiveInfo, error) {
+	keys, err := a.target.List(ctx, fmt.Sprintf("archive/%s/", table))
+	if err != nil {
+		return nil, fmt.Errorf("archive: list archives for %s: %w", table, err)
+	}
+
+	infos := make([]database.ArchiveInfo, 0, len(keys))
+	for _, key := range keys {
+		if !strings.HasSuffix(key, ".json") {
+			continue
+		}
+		var manifest archiveManifest
+		if err := a.readJSON(ctx, key, &manifest); err != nil {
+			return nil, fmt.Errorf("archive: load manifest %s: %w", key, err)
+		}
+		infos = append(infos, database.ArchiveInfo{
+			ID:          manifest.ID,
+			Table:       manifest.Table,
+			RecordCount: manifest.RecordCount,
+			Size:        manifest.Size,
+			Compressed:  manifest.Compressed,
+			CreatedAt:   manifest.CreatedAt,
+		})
+	}
+	return infos, nil
+}
+
+func archiveManifestKey(table, archiveID string) string {
+	return fmt.Sprintf("archive/%s/%s.json", table, archiveID)
+}
+
+func archivePointerKey(archiveID string) string {
+	return "archive/by-id/" + archiveID + ".json"
+}
+
+func (a *ArchiveService) writeJSON(ctx context.Context, key string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = a.target.Write(ctx, key, bytes.NewReader(data))
+	return err
+}
+
+func (a *ArchiveService) readJSON(ctx context.Context, key string, v interface{}) error {
+	rc, err := a.target.Open(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	return json.NewDecoder(rc).Decode(v)
+}
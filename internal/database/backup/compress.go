@@ -0,0 +1,84 @@
+package backup
+
+import (
+	"compress/gzip"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/awesomeapibrasil/gateway-worker/internal/database"
+)
+
+// Compressor wraps a stream codec used for backup/archive payloads. Ext is
+// appended to the object key so RestoreBackup/RestoreArchivedData can pick
+// the matching decompressor without needing the original config.
+type Compressor interface {
+	Ext() string
+	NewWriter(w io.Writer) (io.WriteCloser, error)
+	NewReader(r io.Reader) (io.ReadCloser, error)
+}
+
+type noopCompressor struct{}
+
+func (noopCompressor) Ext() string                                   { return "" }
+func (noopCompressor) NewWriter(w io.Writer) (io.WriteCloser, error) { return nopWriteCloser{w}, nil }
+func (noopCompressor) NewReader(r io.Reader) (io.ReadCloser, error)  { return io.NopCloser(r), nil }
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
+
+type gzipCompressor struct{}
+
+func (gzipCompressor) Ext() string { return ".gz" }
+
+func (gzipCompressor) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return gzip.NewWriter(w), nil
+}
+
+func (gzipCompressor) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return gzip.NewReader(r)
+}
+
+type zstdCompressor struct{}
+
+func (zstdCompressor) Ext() string { return ".zst" }
+
+func (zstdCompressor) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return zstd.NewWriter(w)
+}
+
+func (zstdCompressor) NewReader(r io.Reader) (io.ReadCloser, error) {
+	dec, err := zstd.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	return dec.IOReadCloser(), nil
+}
+
+// compressorFor picks the codec named by config, defaulting to gzip when
+// config.Compress is set but no algorithm is named.
+func compressorFor(config database.BackupConfig) Compressor {
+	if !config.Compress {
+		return noopCompressor{}
+	}
+	if config.CompressionAlgo == "zstd" {
+		return zstdCompressor{}
+	}
+	return gzipCompressor{}
+}
+
+// compressorForExt picks the codec a stored object was written with, read
+// back from its key's extension - this lets restore honor whatever
+// algorithm was in effect at backup time, even if the default has since
+// changed.
+func compressorForExt(ext string) Compressor {
+	switch ext {
+	case ".gz":
+		return gzipCompressor{}
+	case ".zst":
+		return zstdCompressor{}
+	default:
+		return noopCompressor{}
+	}
+}
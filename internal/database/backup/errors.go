@@ -0,0 +1,12 @@
+package backup
+
+import "errors"
+
+// joinErrors wraps errors.Join, returning nil for an empty slice so callers
+// can pass straight through without an extra len check.
+func joinErrors(errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return errors.Join(errs...)
+}
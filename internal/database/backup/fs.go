@@ -0,0 +1,93 @@
+package backup
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func init() {
+	RegisterTarget("fs", func(ctx context.Context, dsn string) (Target, error) {
+		return NewFSTarget(dsn), nil
+	})
+}
+
+// fsTarget is a single-node Target that stores each key as a file under dir,
+// preserving the key's slash-separated structure as nested directories.
+type fsTarget struct {
+	dir string
+}
+
+// NewFSTarget builds a Target rooted at dir, creating it if necessary.
+func NewFSTarget(dir string) Target {
+	return &fsTarget{dir: dir}
+}
+
+func (t *fsTarget) path(key string) string {
+	return filepath.Join(t.dir, filepath.FromSlash(key))
+}
+
+func (t *fsTarget) Write(ctx context.Context, key string, r io.Reader) (int64, error) {
+	path := t.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return 0, err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	return io.Copy(f, r)
+}
+
+func (t *fsTarget) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	f, err := os.Open(t.path(key))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return f, nil
+}
+
+func (t *fsTarget) Delete(ctx context.Context, key string) error {
+	if err := os.Remove(t.path(key)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (t *fsTarget) List(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	err := filepath.WalkDir(t.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(t.dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if strings.HasPrefix(rel, prefix) {
+			keys = append(keys, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
@@ -0,0 +1,81 @@
+//go:build !nogcs
+
+package backup
+
+import (
+	"context"
+	"io"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/iterator"
+)
+
+func init() {
+	RegisterTarget("gcs", func(ctx context.Context, dsn string) (Target, error) {
+		// dsn is just the bucket name.
+		return NewGCSTarget(ctx, dsn)
+	})
+}
+
+// gcsTarget is a Target backed by a Google Cloud Storage bucket.
+type gcsTarget struct {
+	client *storage.Client
+	bucket string
+}
+
+// NewGCSTarget builds a Target backed by bucket, using application default
+// credentials.
+func NewGCSTarget(ctx context.Context, bucket string) (Target, error) {
+	client, err := storage.NewClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &gcsTarget{client: client, bucket: bucket}, nil
+}
+
+func (t *gcsTarget) Write(ctx context.Context, key string, r io.Reader) (int64, error) {
+	w := t.client.Bucket(t.bucket).Object(key).NewWriter(ctx)
+	n, err := io.Copy(w, r)
+	if err != nil {
+		w.Close()
+		return 0, err
+	}
+	if err := w.Close(); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+func (t *gcsTarget) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	rc, err := t.client.Bucket(t.bucket).Object(key).NewReader(ctx)
+	if err != nil {
+		if err == storage.ErrObjectNotExist {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return rc, nil
+}
+
+func (t *gcsTarget) Delete(ctx context.Context, key string) error {
+	if err := t.client.Bucket(t.bucket).Object(key).Delete(ctx); err != nil && err != storage.ErrObjectNotExist {
+		return err
+	}
+	return nil
+}
+
+func (t *gcsTarget) List(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	it := t.client.Bucket(t.bucket).Objects(ctx, &storage.Query{Prefix: prefix})
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, attrs.Name)
+	}
+	return keys, nil
+}
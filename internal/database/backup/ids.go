@@ -0,0 +1,19 @@
+package backup
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// generateID returns a short random hex identifier, prefixed so backup and
+// archive IDs stay visually distinct in logs and object keys. It falls back
+// to a timestamp if the system's random source is unavailable.
+func generateID(prefix string) string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Sprintf("%s-%d", prefix, time.Now().UnixNano())
+	}
+	return prefix + "-" + hex.EncodeToString(buf)
+}
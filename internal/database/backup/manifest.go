@@ -0,0 +1,37 @@
+package backup
+
+import "time"
+
+// Cursor marks how far a table has been backed up, so the next incremental
+// backup only needs to read what changed since. A TableSource reports
+// whichever of LSN or Timestamp it understands; the other is left zero.
+type Cursor struct {
+	LSN       string    `json:"lsn,omitempty"`
+	Timestamp time.Time `json:"timestamp,omitempty"`
+}
+
+// TableManifest is one table's entry in a backup Manifest.
+type TableManifest struct {
+	Name     string  `json:"name"`
+	Key      string  `json:"key"`
+	Size     int64   `json:"size"`
+	Checksum string  `json:"checksum"` // sha256, hex-encoded, of the stored (possibly compressed) bytes
+	Cursor   *Cursor `json:"cursor,omitempty"`
+}
+
+// Manifest describes everything written for one backup: which tables it
+// covers, where each one lives in the Target, and - for incrementals - the
+// parent backup it builds on.
+type Manifest struct {
+	ID          string          `json:"id"`
+	ParentID    string          `json:"parent_id,omitempty"`
+	Name        string          `json:"name"`
+	Incremental bool            `json:"incremental"`
+	Compress    bool            `json:"compress"`
+	CreatedAt   time.Time       `json:"created_at"`
+	Tables      []TableManifest `json:"tables"`
+}
+
+func manifestKey(backupID string) string {
+	return "manifest/" + backupID + ".json"
+}
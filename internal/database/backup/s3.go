@@ -0,0 +1,93 @@
+//go:build !nos3
+
+package backup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+func init() {
+	RegisterTarget("s3", func(ctx context.Context, dsn string) (Target, error) {
+		return newS3TargetFromDSN(dsn)
+	})
+}
+
+// s3Target is a Target backed by an S3 or MinIO-compatible bucket.
+type s3Target struct {
+	client *minio.Client
+	bucket string
+}
+
+// NewS3Target builds a Target backed by the given bucket. endpoint is
+// host:port with no scheme; useSSL selects http vs https.
+func NewS3Target(endpoint, accessKey, secretKey, bucket string, useSSL bool) (Target, error) {
+	client, err := minio.New(endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(accessKey, secretKey, ""),
+		Secure: useSSL,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("backup: create s3 client: %w", err)
+	}
+	return &s3Target{client: client, bucket: bucket}, nil
+}
+
+// newS3TargetFromDSN parses a DSN of the form
+// "s3://accessKey:secretKey@endpoint/bucket?ssl=false".
+func newS3TargetFromDSN(dsn string) (Target, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("backup: parse s3 dsn: %w", err)
+	}
+
+	secretKey, _ := u.User.Password()
+	bucket := strings.TrimPrefix(u.Path, "/")
+	useSSL := u.Query().Get("ssl") != "false"
+
+	return NewS3Target(u.Host, u.User.Username(), secretKey, bucket, useSSL)
+}
+
+func (t *s3Target) Write(ctx context.Context, key string, r io.Reader) (int64, error) {
+	info, err := t.client.PutObject(ctx, t.bucket, key, r, -1, minio.PutObjectOptions{})
+	if err != nil {
+		return 0, err
+	}
+	return info.Size, nil
+}
+
+func (t *s3Target) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	obj, err := t.client.GetObject(ctx, t.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := obj.Stat(); err != nil {
+		obj.Close()
+		resp := minio.ToErrorResponse(err)
+		if resp.Code == "NoSuchKey" {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return obj, nil
+}
+
+func (t *s3Target) Delete(ctx context.Context, key string) error {
+	return t.client.RemoveObject(ctx, t.bucket, key, minio.RemoveObjectOptions{})
+}
+
+func (t *s3Target) List(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	for obj := range t.client.ListObjects(ctx, t.bucket, minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+		if obj.Err != nil {
+			return nil, obj.Err
+		}
+		keys = append(keys, obj.Key)
+	}
+	return keys, nil
+}
@@ -0,0 +1,311 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/awesomeapibrasil/gateway-worker/internal/database"
+)
+
+// TableSource is how Service reads table data to back up. since is nil for
+// a full backup; for an incremental backup it's the Cursor the previous
+// backup of that table left off at. ReadTable returns the new Cursor to
+// persist once the returned data has been written successfully.
+type TableSource interface {
+	Tables(ctx context.Context) ([]string, error)
+	ReadTable(ctx context.Context, table string, since *Cursor) (io.Reader, *Cursor, error)
+}
+
+// TableSink is how Service writes table data back during a restore.
+type TableSink interface {
+	WriteTable(ctx context.Context, table string, r io.Reader) error
+}
+
+// Service implements database.BackupService against a pluggable Target,
+// reading/writing tables through a TableSource/TableSink pair so it stays
+// agnostic to whatever actually stores the data.
+type Service struct {
+	target Target
+	source TableSource
+	sink   TableSink
+	state  StateStore
+}
+
+// NewService builds a Service. sink may be nil if restores are never
+// expected against this Service (e.g. a backup-only replica).
+func NewService(target Target, source TableSource, sink TableSink, state StateStore) *Service {
+	return &Service{target: target, source: source, sink: sink, state: state}
+}
+
+// CreateBackup snapshots config.Tables (or every table TableSource knows
+// about, if empty) into target, writing one compressed object per table
+// plus a Manifest tying them together with sizes, checksums, and - for
+// incremental backups - the cursor to resume from next time.
+func (s *Service) CreateBackup(ctx context.Context, config database.BackupConfig) (*database.BackupResult, error) {
+	id := generateID("backup")
+	compressor := compressorFor(config)
+
+	tables := config.Tables
+	if len(tables) == 0 {
+		t, err := s.source.Tables(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("backup: list tables: %w", err)
+		}
+		tables = t
+	}
+
+	manifest := Manifest{
+		ID:          id,
+		Name:        config.Name,
+		Incremental: config.Incremental,
+		Compress:    config.Compress,
+		CreatedAt:   time.Now(),
+	}
+
+	var totalSize int64
+	for _, table := range tables {
+		tm, err := s.backupTable(ctx, id, table, config, compressor)
+		if err != nil {
+			return nil, err
+		}
+		manifest.Tables = append(manifest.Tables, *tm)
+		totalSize += tm.Size
+	}
+
+	if config.Incremental {
+		parentID, err := s.state.LoadLatestBackupID(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("backup: load parent backup id: %w", err)
+		}
+		manifest.ParentID = parentID
+	}
+
+	manifestData, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("backup: encode manifest: %w", err)
+	}
+	if _, err := s.target.Write(ctx, manifestKey(id), bytes.NewReader(manifestData)); err != nil {
+		return nil, fmt.Errorf("backup: write manifest: %w", err)
+	}
+
+	if err := s.state.SaveLatestBackupID(ctx, id); err != nil {
+		return nil, fmt.Errorf("backup: save latest backup id: %w", err)
+	}
+
+	return &database.BackupResult{
+		ID:          id,
+		Name:        config.Name,
+		Size:        totalSize,
+		Compressed:  config.Compress,
+		Incremental: config.Incremental,
+		CreatedAt:   manifest.CreatedAt,
+	}, nil
+}
+
+func (s *Service) backupTable(ctx context.Context, backupID, table string, config database.BackupConfig, compressor Compressor) (*TableManifest, error) {
+	var since *Cursor
+	if config.Incremental {
+		cursor, err := s.state.LoadCursor(ctx, table)
+		if err != nil {
+			return nil, fmt.Errorf("backup: load cursor for %s: %w", table, err)
+		}
+		since = cursor
+	}
+
+	data, newCursor, err := s.source.ReadTable(ctx, table, since)
+	if err != nil {
+		return nil, fmt.Errorf("backup: read table %s: %w", table, err)
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		cw, err := compressor.NewWriter(pw)
+		if err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+		_, copyErr := io.Copy(cw, data)
+		closeErr := cw.Close()
+		if copyErr != nil {
+			pw.CloseWithError(copyErr)
+			return
+		}
+		pw.CloseWithError(closeErr)
+	}()
+
+	hasher := sha256.New()
+	key := fmt.Sprintf("%s/%s%s", backupID, table, compressor.Ext())
+
+	size, err := s.target.Write(ctx, key, io.TeeReader(pr, hasher))
+	if err != nil {
+		return nil, fmt.Errorf("backup: write table %s: %w", table, err)
+	}
+
+	if config.Incremental && newCursor != nil {
+		if err := s.state.SaveCursor(ctx, table, newCursor); err != nil {
+			return nil, fmt.Errorf("backup: save cursor for %s: %w", table, err)
+		}
+	}
+
+	return &TableManifest{
+		Name:     table,
+		Key:      key,
+		Size:     size,
+		Checksum: hex.EncodeToString(hasher.Sum(nil)),
+		Cursor:   newCursor,
+	}, nil
+}
+
+// RestoreBackup restores every table in backupID's manifest.
+func (s *Service) RestoreBackup(ctx context.Context, backupID string) error {
+	return s.RestoreTables(ctx, backupID, nil)
+}
+
+// RestoreTables restores only the named tables from backupID's manifest; a
+// nil or empty tables restores everything, matching RestoreBackup.
+func (s *Service) RestoreTables(ctx context.Context, backupID string, tables []string) error {
+	manifest, err := s.loadManifest(ctx, manifestKey(backupID))
+	if err != nil {
+		return err
+	}
+
+	wanted := make(map[string]bool, len(tables))
+	for _, t := range tables {
+		wanted[t] = true
+	}
+
+	for _, tm := range manifest.Tables {
+		if len(wanted) > 0 && !wanted[tm.Name] {
+			continue
+		}
+		if err := s.restoreTable(ctx, tm); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) restoreTable(ctx context.Context, tm TableManifest) error {
+	rc, err := s.target.Open(ctx, tm.Key)
+	if err != nil {
+		return fmt.Errorf("backup: open table %s: %w", tm.Name, err)
+	}
+	defer rc.Close()
+
+	hasher := sha256.New()
+	dr, err := compressorForExt(filepath.Ext(tm.Key)).NewReader(io.TeeReader(rc, hasher))
+	if err != nil {
+		return fmt.Errorf("backup: decompress table %s: %w", tm.Name, err)
+	}
+	defer dr.Close()
+
+	if err := s.sink.WriteTable(ctx, tm.Name, dr); err != nil {
+		return fmt.Errorf("backup: restore table %s: %w", tm.Name, err)
+	}
+
+	if checksum := hex.EncodeToString(hasher.Sum(nil)); checksum != tm.Checksum {
+		return fmt.Errorf("backup: table %s failed checksum verification (manifest %s, got %s)", tm.Name, tm.Checksum, checksum)
+	}
+	return nil
+}
+
+// ListBackups returns every backup with a manifest in target, newest first.
+func (s *Service) ListBackups(ctx context.Context) ([]database.BackupInfo, error) {
+	manifests, err := s.listManifests(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]database.BackupInfo, 0, len(manifests))
+	for _, m := range manifests {
+		var size int64
+		for _, tm := range m.Tables {
+			size += tm.Size
+		}
+		infos = append(infos, database.BackupInfo{
+			ID:          m.ID,
+			Name:        m.Name,
+			Size:        size,
+			Compressed:  m.Compress,
+			Incremental: m.Incremental,
+			CreatedAt:   m.CreatedAt,
+		})
+	}
+
+	sort.Slice(infos, func(i, j int) bool { return infos[i].CreatedAt.After(infos[j].CreatedAt) })
+	return infos, nil
+}
+
+// DeleteOldBackups removes every backup (manifest and table objects) older
+// than retentionDays, aggregating per-backup failures so one bad delete
+// doesn't stop the rest from being cleaned up.
+func (s *Service) DeleteOldBackups(ctx context.Context, retentionDays int) error {
+	manifests, err := s.listManifests(ctx)
+	if err != nil {
+		return err
+	}
+
+	cutoff := time.Now().AddDate(0, 0, -retentionDays)
+	var errs []error
+	for _, m := range manifests {
+		if m.CreatedAt.After(cutoff) {
+			continue
+		}
+		if err := s.deleteBackup(ctx, m); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return joinErrors(errs)
+}
+
+func (s *Service) deleteBackup(ctx context.Context, m Manifest) error {
+	var errs []error
+	for _, tm := range m.Tables {
+		if err := s.target.Delete(ctx, tm.Key); err != nil {
+			errs = append(errs, fmt.Errorf("delete table %s for backup %s: %w", tm.Name, m.ID, err))
+		}
+	}
+	if err := s.target.Delete(ctx, manifestKey(m.ID)); err != nil {
+		errs = append(errs, fmt.Errorf("delete manifest for backup %s: %w", m.ID, err))
+	}
+	return joinErrors(errs)
+}
+
+func (s *Service) loadManifest(ctx context.Context, key string) (*Manifest, error) {
+	rc, err := s.target.Open(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("backup: open manifest %s: %w", key, err)
+	}
+	defer rc.Close()
+
+	var manifest Manifest
+	if err := json.NewDecoder(rc).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("backup: decode manifest %s: %w", key, err)
+	}
+	return &manifest, nil
+}
+
+func (s *Service) listManifests(ctx context.Context) ([]Manifest, error) {
+	keys, err := s.target.List(ctx, "manifest/")
+	if err != nil {
+		return nil, fmt.Errorf("backup: list manifests: %w", err)
+	}
+
+	manifests := make([]Manifest, 0, len(keys))
+	for _, key := range keys {
+		m, err := s.loadManifest(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		manifests = append(manifests, *m)
+	}
+	return manifests, nil
+}
@@ -0,0 +1,74 @@
+package backup
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/awesomeapibrasil/gateway-worker/internal/storage"
+)
+
+const (
+	cursorKeyPrefix = "backup/cursor/"
+	latestBackupKey = "backup/latest"
+)
+
+// StateStore persists per-table incremental cursors and the most recent
+// backup ID across restarts, so the Service doesn't fall back to a full
+// backup (or lose the incremental chain's parent link) every time the
+// process restarts.
+type StateStore interface {
+	LoadCursor(ctx context.Context, table string) (*Cursor, error)
+	SaveCursor(ctx context.Context, table string, cursor *Cursor) error
+	LoadLatestBackupID(ctx context.Context) (string, error)
+	SaveLatestBackupID(ctx context.Context, id string) error
+}
+
+// kvStateStore implements StateStore on top of the generic storage.KV
+// already used for certificate/configuration persistence.
+type kvStateStore struct {
+	kv storage.KV
+}
+
+// NewStateStore builds a StateStore backed by kv.
+func NewStateStore(kv storage.KV) StateStore {
+	return &kvStateStore{kv: kv}
+}
+
+func (s *kvStateStore) LoadCursor(ctx context.Context, table string) (*Cursor, error) {
+	data, err := s.kv.Get(ctx, cursorKeyPrefix+table)
+	if err != nil {
+		if err == storage.ErrNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, err
+	}
+	return &cursor, nil
+}
+
+func (s *kvStateStore) SaveCursor(ctx context.Context, table string, cursor *Cursor) error {
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return err
+	}
+	return s.kv.Put(ctx, cursorKeyPrefix+table, data, 0)
+}
+
+func (s *kvStateStore) LoadLatestBackupID(ctx context.Context) (string, error) {
+	data, err := s.kv.Get(ctx, latestBackupKey)
+	if err != nil {
+		if err == storage.ErrNotFound {
+			return "", nil
+		}
+		return "", err
+	}
+	return string(data), nil
+}
+
+func (s *kvStateStore) SaveLatestBackupID(ctx context.Context, id string) error {
+	return s.kv.Put(ctx, latestBackupKey, []byte(id), 0)
+}
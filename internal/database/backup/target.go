@@ -0,0 +1,57 @@
+// Package backup implements concrete database.BackupService and
+// database.Archiver backends: a pluggable object-store Target (local
+// filesystem, S3/MinIO-compatible, GCS), gzip/zstd compression, and the
+// manifest bookkeeping that makes incremental backups and partial-table
+// restores possible.
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrNotFound is returned by Target.Open when key does not exist.
+var ErrNotFound = errors.New("backup: object not found")
+
+// Target is the object-store abstraction every backup/archive destination
+// implements. Keys are slash-separated paths (e.g. "manifest/<id>.json",
+// "<id>/<table>.gz"); it is up to each driver to map that onto its own
+// storage model.
+type Target interface {
+	// Write stores the contents of r at key, returning the number of bytes
+	// written.
+	Write(ctx context.Context, key string, r io.Reader) (size int64, err error)
+
+	// Open returns a reader for key, or ErrNotFound.
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// Delete removes key. Deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+
+	// List returns every key with the given prefix, in no particular order.
+	List(ctx context.Context, prefix string) ([]string, error)
+}
+
+// TargetFactory constructs a Target from a driver-specific DSN.
+type TargetFactory func(ctx context.Context, dsn string) (Target, error)
+
+var targets = make(map[string]TargetFactory)
+
+// RegisterTarget makes a driver available under name. Build-tag-guarded
+// driver files call this from an init() func, mirroring internal/storage's
+// driver registration.
+func RegisterTarget(name string, factory TargetFactory) {
+	targets[name] = factory
+}
+
+// OpenTarget constructs the Target registered under name (e.g. "fs", "s3",
+// "gcs"), connecting it with dsn.
+func OpenTarget(ctx context.Context, name, dsn string) (Target, error) {
+	factory, ok := targets[name]
+	if !ok {
+		return nil, fmt.Errorf("backup: unknown target %q", name)
+	}
+	return factory(ctx, dsn)
+}
@@ -4,9 +4,28 @@ package database
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
+
+	"github.com/awesomeapibrasil/gateway-worker/internal/lock"
 )
 
+// maintenanceStepTimeout bounds each step of RunMaintenance so a single
+// hung step (a stuck backup target, an unreachable object store) can't
+// block the rest of the routine indefinitely.
+const maintenanceStepTimeout = 10 * time.Minute
+
+// defaultRetentionDays is used by RunMaintenance's cleanup and backup
+// retention steps when the Manager hasn't been given an explicit policy.
+const defaultRetentionDays = 30
+
+// defaultLockTTL is the lease duration every maintenance entry point
+// acquires its named lock for when a Locker is set. lock.RunExclusive
+// renews well before this elapses (see lock.renewFraction), so it only
+// matters if a step hangs long enough to miss every renewal.
+const defaultLockTTL = 2 * time.Minute
+
 // Manager handles database operations and maintenance
 type Manager struct {
 	migrator  Migrator
@@ -14,6 +33,18 @@ type Manager struct {
 	archiver  Archiver
 	optimizer Optimizer
 	backup    BackupService
+	locker    lock.DistributedLocker
+
+	cleanupTables   []CleanupTableConfig
+	archivalConfigs []ArchivalConfig
+	retentionDays   int
+}
+
+// CleanupTableConfig names a table PerformCleanup should sweep for expired
+// rows, and the column that holds each row's expiry time.
+type CleanupTableConfig struct {
+	Table       string
+	ExpiryField string
 }
 
 // Migrator interface for database schema migrations
@@ -66,65 +97,65 @@ type Migration struct {
 
 // ArchivalConfig represents archival configuration
 type ArchivalConfig struct {
-	Table         string        `json:"table"`
-	TimeField     string        `json:"time_field"`
-	RetentionDays int           `json:"retention_days"`
-	BatchSize     int           `json:"batch_size"`
-	Compress      bool          `json:"compress"`
+	Table         string `json:"table"`
+	TimeField     string `json:"time_field"`
+	RetentionDays int    `json:"retention_days"`
+	BatchSize     int    `json:"batch_size"`
+	Compress      bool   `json:"compress"`
 }
 
 // ArchivalResult represents the result of an archival operation
 type ArchivalResult struct {
-	ArchiveID      string    `json:"archive_id"`
-	Table          string    `json:"table"`
-	RecordsArchived int64    `json:"records_archived"`
-	CompressedSize  int64    `json:"compressed_size"`
-	CreatedAt      time.Time `json:"created_at"`
+	ArchiveID       string    `json:"archive_id"`
+	Table           string    `json:"table"`
+	RecordsArchived int64     `json:"records_archived"`
+	CompressedSize  int64     `json:"compressed_size"`
+	CreatedAt       time.Time `json:"created_at"`
 }
 
 // ArchiveInfo represents information about an archive
 type ArchiveInfo struct {
-	ID            string    `json:"id"`
-	Table         string    `json:"table"`
-	RecordCount   int64     `json:"record_count"`
-	Size          int64     `json:"size"`
-	Compressed    bool      `json:"compressed"`
-	CreatedAt     time.Time `json:"created_at"`
+	ID          string    `json:"id"`
+	Table       string    `json:"table"`
+	RecordCount int64     `json:"record_count"`
+	Size        int64     `json:"size"`
+	Compressed  bool      `json:"compressed"`
+	CreatedAt   time.Time `json:"created_at"`
 }
 
 // PerformanceReport represents database performance analysis
 type PerformanceReport struct {
-	GeneratedAt    time.Time           `json:"generated_at"`
-	OverallHealth  string              `json:"overall_health"`
-	SlowQueries    []SlowQuery         `json:"slow_queries"`
-	IndexUsage     []IndexUsageInfo    `json:"index_usage"`
-	TableSizes     []TableSizeInfo     `json:"table_sizes"`
+	GeneratedAt     time.Time                    `json:"generated_at"`
+	OverallHealth   string                       `json:"overall_health"`
+	SlowQueries     []SlowQuery                  `json:"slow_queries"`
+	IndexUsage      []IndexUsageInfo             `json:"index_usage"`
+	TableSizes      []TableSizeInfo              `json:"table_sizes"`
 	Recommendations []OptimizationRecommendation `json:"recommendations"`
 }
 
 // SlowQuery represents a slow database query
 type SlowQuery struct {
-	Query         string        `json:"query"`
-	AvgDuration   time.Duration `json:"avg_duration"`
-	ExecutionCount int64        `json:"execution_count"`
-	LastSeen      time.Time     `json:"last_seen"`
+	Query          string        `json:"query"`
+	AvgDuration    time.Duration `json:"avg_duration"`
+	ExecutionCount int64         `json:"execution_count"`
+	LastSeen       time.Time     `json:"last_seen"`
 }
 
 // IndexUsageInfo represents index usage statistics
 type IndexUsageInfo struct {
-	Table      string `json:"table"`
-	Index      string `json:"index"`
-	Scans      int64  `json:"scans"`
-	TupleReads int64  `json:"tuple_reads"`
-	TupleFetches int64 `json:"tuple_fetches"`
+	Table        string `json:"table"`
+	Index        string `json:"index"`
+	Scans        int64  `json:"scans"`
+	TupleReads   int64  `json:"tuple_reads"`
+	TupleFetches int64  `json:"tuple_fetches"`
 }
 
 // TableSizeInfo represents table size information
 type TableSizeInfo struct {
-	Table      string `json:"table"`
-	Size       int64  `json:"size"`
-	RowCount   int64  `json:"row_count"`
-	IndexSize  int64  `json:"index_size"`
+	Table     string `json:"table"`
+	Size      int64  `json:"size"`
+	RowCount  int64  `json:"row_count"`
+	IndexSize int64  `json:"index_size"`
 }
 
 // OptimizationRecommendation represents a database optimization recommendation
@@ -139,10 +170,13 @@ type OptimizationRecommendation struct {
 
 // BackupConfig represents backup configuration
 type BackupConfig struct {
-	Name        string   `json:"name"`
-	Tables      []string `json:"tables"`
-	Compress    bool     `json:"compress"`
-	Incremental bool     `json:"incremental"`
+	Name     string   `json:"name"`
+	Tables   []string `json:"tables"`
+	Compress bool     `json:"compress"`
+	// CompressionAlgo selects the codec when Compress is set: "gzip"
+	// (default) or "zstd".
+	CompressionAlgo string `json:"compression_algo,omitempty"`
+	Incremental     bool   `json:"incremental"`
 }
 
 // BackupResult represents the result of a backup operation
@@ -168,55 +202,186 @@ type BackupInfo struct {
 // New creates a new database manager
 func New(migrator Migrator, cleaner Cleaner, archiver Archiver, optimizer Optimizer, backup BackupService) *Manager {
 	return &Manager{
-		migrator:  migrator,
-		cleaner:   cleaner,
-		archiver:  archiver,
-		optimizer: optimizer,
-		backup:    backup,
+		migrator:      migrator,
+		cleaner:       cleaner,
+		archiver:      archiver,
+		optimizer:     optimizer,
+		backup:        backup,
+		retentionDays: defaultRetentionDays,
 	}
 }
 
-// RunMaintenance runs routine database maintenance tasks
+// SetCleanupTables configures which tables PerformCleanup/RunMaintenance
+// sweep for expired rows. Tables not listed here are left untouched.
+func (m *Manager) SetCleanupTables(tables []CleanupTableConfig) {
+	m.cleanupTables = tables
+}
+
+// SetArchivalConfigs configures which tables RunMaintenance archives old
+// data from, and how.
+func (m *Manager) SetArchivalConfigs(configs []ArchivalConfig) {
+	m.archivalConfigs = configs
+}
+
+// SetRetentionDays overrides the default retention window (30 days) used by
+// PerformCleanup and backup retention during RunMaintenance.
+func (m *Manager) SetRetentionDays(days int) {
+	m.retentionDays = days
+}
+
+// SetLocker makes every maintenance entry point (RunMaintenance,
+// ApplyMigrations, CreateBackup, PerformCleanup, and the archival step
+// inside RunMaintenance) acquire a named lease from locker before running,
+// so at most one replica executes a given one at a time. Unset (the
+// default), they run unguarded - fine for a single-instance deployment.
+func (m *Manager) SetLocker(locker lock.DistributedLocker) {
+	m.locker = locker
+}
+
+// runExclusive runs fn under a lease named name when a Locker is set,
+// otherwise runs it directly. A lock.ErrLockHeld (another replica is
+// already running this step) is swallowed rather than surfaced as a
+// failure, per lock.RunExclusive's contract.
+func (m *Manager) runExclusive(ctx context.Context, name string, fn func(context.Context) error) error {
+	if m.locker == nil {
+		return fn(ctx)
+	}
+
+	err := lock.RunExclusive(ctx, m.locker, name, defaultLockTTL, fn)
+	if errors.Is(err, lock.ErrLockHeld) {
+		return nil
+	}
+	return err
+}
+
+// RunMaintenance runs the routine maintenance sequence - migrations,
+// cleanup, archival, optimization analysis, then a backup - in order.
+// Each step runs under its own timeout and a failure in one step is
+// recorded but doesn't prevent the rest from running; the returned error,
+// if any, joins every step's failure.
 func (m *Manager) RunMaintenance(ctx context.Context) error {
-	// TODO: Implement maintenance routine
-	// 1. Check for pending migrations
-	// 2. Clean expired data
-	// 3. Archive old data
-	// 4. Optimize performance
-	// 5. Create backups
+	return m.runExclusive(ctx, "db/run-maintenance", func(ctx context.Context) error {
+		var errs []error
+
+		if err := m.runStep(ctx, "migrations", func(ctx context.Context) error {
+			return m.ApplyMigrations(ctx)
+		}); err != nil {
+			errs = append(errs, err)
+		}
+
+		if err := m.runStep(ctx, "cleanup", func(ctx context.Context) error {
+			_, err := m.PerformCleanup(ctx, m.retentionDays)
+			return err
+		}); err != nil {
+			errs = append(errs, err)
+		}
+
+		for _, cfg := range m.archivalConfigs {
+			cfg := cfg
+			if err := m.runStep(ctx, fmt.Sprintf("archive(%s)", cfg.Table), func(ctx context.Context) error {
+				return m.runExclusive(ctx, "db/archive:"+cfg.Table, func(ctx context.Context) error {
+					_, err := m.archiver.ArchiveOldData(ctx, cfg)
+					return err
+				})
+			}); err != nil {
+				errs = append(errs, err)
+			}
+		}
+
+		if err := m.runStep(ctx, "optimize", func(ctx context.Context) error {
+			_, err := m.optimizer.AnalyzePerformance(ctx)
+			return err
+		}); err != nil {
+			errs = append(errs, err)
+		}
+
+		if err := m.runStep(ctx, "backup", func(ctx context.Context) error {
+			_, err := m.CreateBackup(ctx, "scheduled")
+			return err
+		}); err != nil {
+			errs = append(errs, err)
+		}
+
+		if err := m.runStep(ctx, "backup retention", func(ctx context.Context) error {
+			return m.backup.DeleteOldBackups(ctx, m.retentionDays)
+		}); err != nil {
+			errs = append(errs, err)
+		}
+
+		return errors.Join(errs...)
+	})
+}
+
+// runStep bounds step by maintenanceStepTimeout and wraps any error with
+// name, so RunMaintenance's joined error says which step failed.
+func (m *Manager) runStep(ctx context.Context, name string, step func(context.Context) error) error {
+	stepCtx, cancel := context.WithTimeout(ctx, maintenanceStepTimeout)
+	defer cancel()
+
+	if err := step(stepCtx); err != nil {
+		return fmt.Errorf("maintenance step %q: %w", name, err)
+	}
 	return nil
 }
 
 // ApplyMigrations applies pending database migrations
 func (m *Manager) ApplyMigrations(ctx context.Context) error {
-	migrations, err := m.migrator.GetPendingMigrations(ctx)
-	if err != nil {
-		return err
-	}
-
-	for _, migration := range migrations {
-		if err := m.migrator.ApplyMigration(ctx, migration); err != nil {
+	return m.runExclusive(ctx, "db/apply-migrations", func(ctx context.Context) error {
+		migrations, err := m.migrator.GetPendingMigrations(ctx)
+		if err != nil {
 			return err
 		}
-	}
 
-	return nil
+		for _, migration := range migrations {
+			if err := m.migrator.ApplyMigration(ctx, migration); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
 }
 
-// PerformCleanup performs data cleanup operations
+// PerformCleanup sweeps every table configured via SetCleanupTables for
+// rows whose expiry field is older than retentionDays.
 func (m *Manager) PerformCleanup(ctx context.Context, retentionDays int) (*CleanupResult, error) {
-	// TODO: Implement cleanup operations
-	return nil, nil
+	result := &CleanupResult{}
+	err := m.runExclusive(ctx, "db/cleanup", func(ctx context.Context) error {
+		return m.performCleanup(ctx, retentionDays, result)
+	})
+	return result, err
+}
+
+// performCleanup is PerformCleanup's body, run under runExclusive's lease.
+func (m *Manager) performCleanup(ctx context.Context, retentionDays int, result *CleanupResult) error {
+	before := time.Now().AddDate(0, 0, -retentionDays)
+
+	for _, cfg := range m.cleanupTables {
+		deleted, err := m.cleaner.CleanExpiredData(ctx, cfg.Table, cfg.ExpiryField, before)
+		if err != nil {
+			return fmt.Errorf("cleanup %s: %w", cfg.Table, err)
+		}
+		result.RecordsDeleted += deleted
+		result.TablesProcessed = append(result.TablesProcessed, cfg.Table)
+	}
+
+	return nil
 }
 
 // CreateBackup creates a database backup
 func (m *Manager) CreateBackup(ctx context.Context, name string) (*BackupResult, error) {
-	config := BackupConfig{
-		Name:     name,
-		Compress: true,
-	}
+	var result *BackupResult
+	err := m.runExclusive(ctx, "db/create-backup:"+name, func(ctx context.Context) error {
+		config := BackupConfig{
+			Name:     name,
+			Compress: true,
+		}
 
-	return m.backup.CreateBackup(ctx, config)
+		backup, err := m.backup.CreateBackup(ctx, config)
+		result = backup
+		return err
+	})
+	return result, err
 }
 
 // AnalyzePerformance analyzes database performance
@@ -226,7 +391,7 @@ func (m *Manager) AnalyzePerformance(ctx context.Context) (*PerformanceReport, e
 
 // CleanupResult represents the result of cleanup operations
 type CleanupResult struct {
-	RecordsDeleted int64 `json:"records_deleted"`
-	SpaceFreed     int64 `json:"space_freed"`
+	RecordsDeleted  int64    `json:"records_deleted"`
+	SpaceFreed      int64    `json:"space_freed"`
 	TablesProcessed []string `json:"tables_processed"`
-}
\ No newline at end of file
+}
@@ -0,0 +1,103 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/awesomeapibrasil/gateway-worker/internal/queue"
+)
+
+// SubmitJobRequest is the SubmitJob RPC payload. Mirrors queue.Job's public
+// fields rather than accepting one directly, since Status/VisibleAt/etc. are
+// server-assigned.
+type SubmitJobRequest struct {
+	Type           queue.JobType
+	Payload        map[string]interface{}
+	Priority       int
+	MaxRetry       int
+	IdempotencyKey string
+}
+
+// SubmitJobResponse returns the server-assigned ID so the caller can poll
+// GetJobStatus for it.
+type SubmitJobResponse struct {
+	JobID string
+}
+
+// SubmitJob enqueues a job on the worker's persistent queue. Already TODO'd
+// in the service surface until protobuf stubs are generated; see the same
+// note on CertificateStream in watch.go.
+func (s *Service) SubmitJob(ctx context.Context, req *SubmitJobRequest) (*SubmitJobResponse, error) {
+	job := &queue.Job{
+		Type:           req.Type,
+		Payload:        req.Payload,
+		Priority:       req.Priority,
+		MaxRetry:       req.MaxRetry,
+		IdempotencyKey: req.IdempotencyKey,
+	}
+	if err := s.queueService.Submit(ctx, job); err != nil {
+		return nil, err
+	}
+	return &SubmitJobResponse{JobID: job.ID}, nil
+}
+
+// JobStatusUpdate is streamed by GetJobStatus whenever the job's status
+// changes, until it reaches a terminal state.
+type JobStatusUpdate struct {
+	JobID     string
+	Status    string
+	Retry     int
+	LastError string
+}
+
+// JobStatusStream is the server-streaming handle a generated
+// GatewayWorkerService_GetJobStatusServer would provide.
+type JobStatusStream interface {
+	Send(*JobStatusUpdate) error
+	Context() interface {
+		Done() <-chan struct{}
+	}
+}
+
+// jobStatusPollInterval bounds how often GetJobStatus re-checks the store;
+// the queue has no push notification for individual job status changes.
+const jobStatusPollInterval = 1 * time.Second
+
+// GetJobStatus streams JobStatusUpdate messages for jobID as its status
+// changes, returning once the job reaches a terminal state (completed or
+// dead-lettered) or the caller disconnects.
+func (s *Service) GetJobStatus(jobID string, stream JobStatusStream) error {
+	ticker := time.NewTicker(jobStatusPollInterval)
+	defer ticker.Stop()
+
+	var lastStatus queue.JobStatus
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		job, err := s.queueService.GetJobStatus(context.Background(), jobID)
+		if err != nil {
+			return err
+		}
+		if job.Status == lastStatus {
+			continue
+		}
+		lastStatus = job.Status
+
+		if err := stream.Send(&JobStatusUpdate{
+			JobID:     job.ID,
+			Status:    string(job.Status),
+			Retry:     job.Retry,
+			LastError: job.LastError,
+		}); err != nil {
+			return err
+		}
+
+		if job.Status == queue.JobStatusCompleted || job.Status == queue.JobStatusDeadLetter {
+			return nil
+		}
+	}
+}
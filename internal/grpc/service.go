@@ -2,38 +2,81 @@ package grpc
 
 import (
 	"context"
-	"crypto/tls"
+	"fmt"
 	"net"
+	"sync"
 	"time"
 
+	"github.com/awesomeapibrasil/gateway-worker/internal/broker"
 	"github.com/awesomeapibrasil/gateway-worker/internal/queue"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 )
 
 // Service provides gRPC functionality for Gateway-Worker communication
 type Service struct {
-	server      *grpc.Server
+	server       *grpc.Server
 	queueService *queue.Service
+
+	certBroker   *broker.Broker
+	configBroker *broker.Broker
+
+	statusMu  sync.RWMutex
+	ackStatus map[string]broker.ClientStatus // gatewayID/topic -> last ack
+
+	mtls *mtlsManager
 }
 
-// New creates a new gRPC service
+// New creates a new gRPC service without transport security. Use
+// NewWithMTLS in production; this constructor remains for local development.
 func New(queueService *queue.Service) *Service {
-	// TODO: Configure TLS credentials for secure communication
-	// For now, we'll use insecure connection for development
-	opts := []grpc.ServerOption{
-		grpc.ConnectionTimeout(10 * time.Second),
+	opts := append(tracingServerOptions(),
+		grpc.ConnectionTimeout(10*time.Second),
+	)
+
+	return newService(queueService, grpc.NewServer(opts...), nil)
+}
+
+// NewWithMTLS creates a gRPC service that requires and verifies client
+// certificates, enforcing SPIFFE ID identities from tlsCfg.AllowedSPIFFEIDs.
+// The trust bundle and server keypair are hot-reloaded from disk on change.
+func NewWithMTLS(queueService *queue.Service, tlsCfg TLSConfig) (*Service, error) {
+	mtls, err := newMTLSManager(tlsCfg)
+	if err != nil {
+		return nil, fmt.Errorf("initialize mTLS: %w", err)
 	}
+	if err := mtls.watch(); err != nil {
+		return nil, fmt.Errorf("watch TLS material: %w", err)
+	}
+
+	creds := credentials.NewTLS(mtls.serverTLSConfig())
+	opts := append(tracingServerOptions(),
+		grpc.ConnectionTimeout(10*time.Second),
+		grpc.Creds(creds),
+	)
 
-	// In production, add TLS credentials:
-	// creds := credentials.NewTLS(&tls.Config{...})
-	// opts = append(opts, grpc.Creds(creds))
+	return newService(queueService, grpc.NewServer(opts...), mtls), nil
+}
 
-	server := grpc.NewServer(opts...)
+// tracingServerOptions installs the otelgrpc interceptors (spans, continuing
+// a trace started by whoever called in) and the RED-metrics interceptors
+// from metrics.go. Shared by New and NewWithMTLS so dev and production
+// builds get the same observability.
+func tracingServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(otelgrpc.UnaryServerInterceptor(), metricsUnaryInterceptor),
+		grpc.ChainStreamInterceptor(otelgrpc.StreamServerInterceptor(), metricsStreamInterceptor),
+	}
+}
 
+func newService(queueService *queue.Service, server *grpc.Server, mtls *mtlsManager) *Service {
 	service := &Service{
-		server:      server,
+		server:       server,
 		queueService: queueService,
+		certBroker:   broker.New(32),
+		configBroker: broker.New(32),
+		mtls:         mtls,
 	}
 
 	// TODO: Register the GatewayWorkerService
@@ -42,6 +85,28 @@ func New(queueService *queue.Service) *Service {
 	return service
 }
 
+// PublishCertificateUpdate notifies every WatchCertificates subscriber that
+// a new certificate version is available. Called by certificate.Manager
+// whenever it stores a renewed certificate.
+func (s *Service) PublishCertificateUpdate(domain, version string, certData []byte) {
+	s.certBroker.Publish(broker.Update{
+		Topic:   domain,
+		Version: version,
+		Payload: &CertificateUpdate{Domain: domain, Version: version, CertData: certData},
+	})
+}
+
+// PublishConfigUpdate notifies every WatchConfiguration subscriber that a
+// new configuration version is available. Called by config.Manager from
+// UpdateWAFRules/UpdateRoutingConfig once the new version is stored.
+func (s *Service) PublishConfigUpdate(configType, version string, data []byte) {
+	s.configBroker.Publish(broker.Update{
+		Topic:   configType,
+		Version: version,
+		Payload: &ConfigUpdate{ConfigType: configType, Version: version, Data: data},
+	})
+}
+
 // Serve starts the gRPC server
 func (s *Service) Serve(ctx context.Context, listener net.Listener) error {
 	// Start server in a goroutine
@@ -59,31 +124,26 @@ func (s *Service) Serve(ctx context.Context, listener net.Listener) error {
 	return nil
 }
 
-// TODO: Implement gRPC service methods when protobuf is generated
+// TODO: Implement remaining gRPC service methods when protobuf is generated
 // This will include methods like:
 // - UpdateCertificate
-// - GetCertificateStatus  
+// - GetCertificateStatus
 // - DeployTemporaryCertificate
 // - UpdateConfiguration
 // - GetConfiguration
 // - UpdateWAFRules
 // - HealthCheck
-// - GetWorkerStatus
-// - SubmitJob
-// - GetJobStatus
-
-// getTLSCredentials configures TLS for secure gRPC communication
-func (s *Service) getTLSCredentials() (credentials.TransportCredentials, error) {
-	// TODO: Implement proper TLS configuration based on WORKER-PURPOSE.md
-	// This should include:
-	// - mTLS authentication for service-to-service communication
-	// - Certificate validation
-	// - Proper certificate authority setup
-	
-	config := &tls.Config{
-		// Configure TLS settings
-		MinVersion: tls.VersionTLS12,
+//
+// WatchCertificates, WatchConfiguration, and GetWorkerStatus are implemented
+// in watch.go, and SubmitJob/GetJobStatus in jobs.go, ahead of codegen,
+// against hand-rolled request/stream types.
+
+// TLSMetrics returns cert expiry, reload count, and rejected-peer-identity
+// counters for the health server to surface, or nil if the service was
+// constructed with New (no mTLS) rather than NewWithMTLS.
+func (s *Service) TLSMetrics() *TLSMetrics {
+	if s.mtls == nil {
+		return nil
 	}
-	
-	return credentials.NewTLS(config), nil
+	return s.mtls.Metrics()
 }
\ No newline at end of file
@@ -0,0 +1,206 @@
+package grpc
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/awesomeapibrasil/gateway-worker/internal/broker"
+)
+
+// WatchRequest is sent once by a Gateway instance when it opens a Watch
+// stream: its identity plus the versions it already knows about, so the
+// Broker can decide what (if anything) to replay before streaming live
+// updates.
+type WatchRequest struct {
+	GatewayID       string
+	KnownVersions   map[string]string // topic (domain / config type) -> version already applied
+}
+
+// CertificateUpdate is streamed to WatchCertificates subscribers.
+type CertificateUpdate struct {
+	Domain   string
+	Version  string
+	CertData []byte
+}
+
+// ConfigUpdate is streamed to WatchConfiguration subscribers.
+type ConfigUpdate struct {
+	ConfigType string
+	Version    string
+	Data       []byte
+}
+
+// CertificateStream is the server-streaming handle a generated
+// GatewayWorkerService_WatchCertificatesServer would provide; defined here
+// by hand until the protobuf stubs are generated (see the TODO in newService).
+type CertificateStream interface {
+	Send(*CertificateUpdate) error
+	Context() interface {
+		Done() <-chan struct{}
+	}
+}
+
+// ConfigStream is the ConfigUpdate equivalent of CertificateStream.
+type ConfigStream interface {
+	Send(*ConfigUpdate) error
+	Context() interface {
+		Done() <-chan struct{}
+	}
+}
+
+// certUpdate pairs a CertificateUpdate with the subscription and topic it
+// came from, so the single sending goroutine in WatchCertificates can Ack
+// and record it after a successful Send.
+type certUpdate struct {
+	sub            *broker.Subscription
+	cu             *CertificateUpdate
+	topic, version string
+}
+
+// WatchCertificates streams CertificateUpdate messages to a connected
+// Gateway instance as certificate.Manager stores new versions, resuming
+// from the versions the client reports it already knows. KnownVersions is
+// keyed by domain (as published by PublishCertificateUpdate), so it
+// subscribes once per domain, mirroring WatchConfiguration.
+func (s *Service) WatchCertificates(req *WatchRequest, stream CertificateStream) error {
+	sctx := streamCtx{stream.Context()}
+
+	merged := make(chan certUpdate)
+	done := make(chan struct{})
+	defer close(done)
+
+	var wg sync.WaitGroup
+	for domain, resumeFrom := range req.KnownVersions {
+		sub := s.certBroker.Subscribe(sctx, req.GatewayID, domain, resumeFrom)
+		defer sub.Close()
+
+		wg.Add(1)
+		go func(sub *broker.Subscription) {
+			defer wg.Done()
+			for update := range sub.Updates() {
+				cu, ok := update.Payload.(*CertificateUpdate)
+				if !ok {
+					continue
+				}
+				select {
+				case merged <- certUpdate{sub: sub, cu: cu, topic: update.Topic, version: update.Version}:
+				case <-done:
+					return
+				}
+			}
+		}(sub)
+	}
+
+	go func() {
+		wg.Wait()
+		close(merged)
+	}()
+
+	// A single goroutine (this one) owns every stream.Send call, since gRPC
+	// forbids concurrent Send on one stream.
+	for u := range merged {
+		if err := stream.Send(u.cu); err != nil {
+			return fmt.Errorf("send certificate update to %s: %w", req.GatewayID, err)
+		}
+		u.sub.Ack(u.version)
+		s.recordAck(req.GatewayID, u.topic, u.version)
+	}
+	return nil
+}
+
+// configUpdate is WatchConfiguration's equivalent of certUpdate.
+type configUpdate struct {
+	sub            *broker.Subscription
+	cu             *ConfigUpdate
+	topic, version string
+}
+
+// WatchConfiguration streams ConfigUpdate messages to a connected Gateway
+// instance as config.Manager publishes new WAF/routing versions.
+func (s *Service) WatchConfiguration(req *WatchRequest, stream ConfigStream) error {
+	sctx := streamCtx{stream.Context()}
+
+	merged := make(chan configUpdate)
+	done := make(chan struct{})
+	defer close(done)
+
+	var wg sync.WaitGroup
+	for configType, resumeFrom := range req.KnownVersions {
+		sub := s.configBroker.Subscribe(sctx, req.GatewayID, configType, resumeFrom)
+		defer sub.Close()
+
+		wg.Add(1)
+		go func(sub *broker.Subscription) {
+			defer wg.Done()
+			for update := range sub.Updates() {
+				cu, ok := update.Payload.(*ConfigUpdate)
+				if !ok {
+					continue
+				}
+				select {
+				case merged <- configUpdate{sub: sub, cu: cu, topic: update.Topic, version: update.Version}:
+				case <-done:
+					return
+				}
+			}
+		}(sub)
+	}
+
+	go func() {
+		wg.Wait()
+		close(merged)
+	}()
+
+	// A single goroutine (this one) owns every stream.Send call, since gRPC
+	// forbids concurrent Send on one stream.
+	for u := range merged {
+		if err := stream.Send(u.cu); err != nil {
+			return fmt.Errorf("send config update to %s: %w", req.GatewayID, err)
+		}
+		u.sub.Ack(u.version)
+		s.recordAck(req.GatewayID, u.topic, u.version)
+	}
+	return nil
+}
+
+// streamCtx adapts the minimal Context().Done() surface a generated gRPC
+// stream exposes to the context.Context the broker expects.
+type streamCtx struct {
+	done interface{ Done() <-chan struct{} }
+}
+
+func (c streamCtx) Deadline() (time.Time, bool)       { return time.Time{}, false }
+func (c streamCtx) Done() <-chan struct{}             { return c.done.Done() }
+func (c streamCtx) Err() error                        { return nil }
+func (c streamCtx) Value(key interface{}) interface{} { return nil }
+
+// recordAck updates the deployment status table consulted by
+// GetWorkerStatus.
+func (s *Service) recordAck(gatewayID, topic, version string) {
+	s.statusMu.Lock()
+	defer s.statusMu.Unlock()
+	if s.ackStatus == nil {
+		s.ackStatus = make(map[string]broker.ClientStatus)
+	}
+	s.ackStatus[gatewayID+"/"+topic] = broker.ClientStatus{
+		ClientID:      gatewayID,
+		Topic:         topic,
+		LastAckedAt:   time.Now(),
+		LastAckedVers: version,
+	}
+}
+
+// GetWorkerStatus reports per-Gateway deployment progress derived from
+// WatchCertificates/WatchConfiguration ACKs. It does not report the worker's
+// uptime.
+func (s *Service) GetWorkerStatus() []broker.ClientStatus {
+	s.statusMu.RLock()
+	defer s.statusMu.RUnlock()
+
+	statuses := make([]broker.ClientStatus, 0, len(s.ackStatus))
+	for _, st := range s.ackStatus {
+		statuses = append(statuses, st)
+	}
+	return statuses
+}
@@ -0,0 +1,75 @@
+package health
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// MetricsRegistry lets each subsystem register its Prometheus collectors
+// under a "group/subgroup" path (e.g. "database/backup", "queue/jobs"),
+// exposed individually at /metrics/v3/{group}/{subgroup} and aggregated at
+// any ancestor path - /metrics/v3/database returns both "database/backup"
+// and "database/migrations" in one scrape. This is the "metrics v3"
+// reorganization used by object-storage projects: it avoids one fat
+// /metrics endpoint and lets Prometheus scrape only what it needs.
+type MetricsRegistry struct {
+	mu     sync.RWMutex
+	groups map[string]*prometheus.Registry
+}
+
+// NewMetricsRegistry creates an empty MetricsRegistry.
+func NewMetricsRegistry() *MetricsRegistry {
+	return &MetricsRegistry{groups: make(map[string]*prometheus.Registry)}
+}
+
+// Register adds collectors under path (leading/trailing slashes are
+// trimmed). Calling Register again with the same path adds to that group
+// rather than replacing it.
+func (r *MetricsRegistry) Register(path string, collectors ...prometheus.Collector) error {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return fmt.Errorf("health: metrics group path must not be empty")
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	reg, ok := r.groups[path]
+	if !ok {
+		reg = prometheus.NewRegistry()
+		r.groups[path] = reg
+	}
+	for _, c := range collectors {
+		if err := reg.Register(c); err != nil {
+			return fmt.Errorf("register collector for group %q: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// Gather returns the metric families for path's exact group plus every
+// descendant group (e.g. path "database" also gathers "database/backup").
+// An empty path gathers every registered group.
+func (r *MetricsRegistry) Gather(path string) ([]*dto.MetricFamily, error) {
+	path = strings.Trim(path, "/")
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var families []*dto.MetricFamily
+	for groupPath, reg := range r.groups {
+		if path != "" && groupPath != path && !strings.HasPrefix(groupPath, path+"/") {
+			continue
+		}
+		mfs, err := reg.Gather()
+		if err != nil {
+			return nil, fmt.Errorf("gather group %q: %w", groupPath, err)
+		}
+		families = append(families, mfs...)
+	}
+	return families, nil
+}
@@ -4,13 +4,18 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"strings"
 	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/common/expfmt"
 )
 
 // Service provides health checking functionality
 type Service struct {
 	startTime time.Time
 	server    *http.Server
+	metrics   *MetricsRegistry
 }
 
 // HealthResponse represents the health check response
@@ -25,14 +30,24 @@ type HealthResponse struct {
 func New() *Service {
 	return &Service{
 		startTime: time.Now(),
+		metrics:   NewMetricsRegistry(),
 	}
 }
 
+// Metrics returns the registry packages use to expose collectors under
+// /metrics/v3/{group}/{subgroup} (main.go registers the queue's collectors
+// under queue/jobs).
+func (s *Service) Metrics() *MetricsRegistry {
+	return s.metrics
+}
+
 // Serve starts the HTTP health server
 func (s *Service) Serve(ctx context.Context, addr string) error {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/health", s.healthHandler)
 	mux.HandleFunc("/ready", s.readinessHandler)
+	mux.Handle("/metrics", promhttp.Handler())
+	mux.HandleFunc("/metrics/v3/", s.metricsV3Handler)
 
 	s.server = &http.Server{
 		Addr:    addr,
@@ -74,4 +89,25 @@ func (s *Service) readinessHandler(w http.ResponseWriter, r *http.Request) {
 	// For now, just return ready
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte("ready"))
+}
+
+// metricsV3Handler serves a path under /metrics/v3/, aggregating every
+// registered group at or below it (so /metrics/v3/database returns both
+// "database/backup" and "database/migrations" in one scrape).
+func (s *Service) metricsV3Handler(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/metrics/v3/")
+
+	families, err := s.metrics.Gather(path)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", string(expfmt.FmtText))
+	enc := expfmt.NewEncoder(w, expfmt.FmtText)
+	for _, mf := range families {
+		if err := enc.Encode(mf); err != nil {
+			return
+		}
+	}
 }
\ No newline at end of file
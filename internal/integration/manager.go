@@ -4,6 +4,8 @@ package integration
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 )
 
@@ -34,6 +36,12 @@ type FeedProcessor interface {
 	ProcessSecurityFeed(ctx context.Context, feedURL string) ([]ThreatIndicator, error)
 	ProcessGeoIPFeed(ctx context.Context, feedURL string) (*GeoIPDatabase, error)
 	ProcessReputationFeed(ctx context.Context, feedURL string) ([]ReputationEntry, error)
+
+	// ProcessFeed fetches source and returns the threat indicators it
+	// currently reports, dispatching to a format-specific parser per
+	// source.Type (STIX/TAXII, MISP, CSV, JSON, or a plain value list). See
+	// feed.go for the default implementation.
+	ProcessFeed(ctx context.Context, source FeedSource) ([]ThreatIndicator, error)
 }
 
 // ReportGenerator interface for generating reports
@@ -65,6 +73,10 @@ type APIResponse struct {
 
 // WebhookConfig represents webhook configuration
 type WebhookConfig struct {
+	// ID identifies this webhook across Subscribe/Unsubscribe/DeliveryHistory
+	// calls; callers choose it (e.g. a UUID minted when the subscription is
+	// created), the same way Alert/Report/Notification IDs are caller-supplied.
+	ID         string            `json:"id"`
 	URL        string            `json:"url"`
 	Events     []string          `json:"events"`
 	Headers    map[string]string `json:"headers"`
@@ -221,14 +233,53 @@ func New(apiClient APIClient, notifier Notifier, feedProcessor FeedProcessor, re
 	}
 }
 
-// ProcessSecurityFeeds processes security threat intelligence feeds
-func (m *Manager) ProcessSecurityFeeds(ctx context.Context, feeds []string) error {
-	// TODO: Implement security feed processing
-	// 1. Download and process threat feeds
-	// 2. Update threat intelligence database
-	// 3. Generate alerts for new threats
-	// 4. Update WAF rules if needed
-	return nil
+// ProcessSecurityFeeds polls each source via the FeedProcessor and raises an
+// Alert for every indicator at or above "high" severity, so the WAF
+// consumer can react to new threats without having to poll indicator state
+// itself.
+func (m *Manager) ProcessSecurityFeeds(ctx context.Context, sources []FeedSource) ([]ThreatIndicator, error) {
+	var all []ThreatIndicator
+	for _, source := range sources {
+		indicators, err := m.feedProcessor.ProcessFeed(ctx, source)
+		if err != nil {
+			return all, fmt.Errorf("process feed %s: %w", source.Name, err)
+		}
+
+		for _, ind := range indicators {
+			if !isHighSeverity(ind.Severity) {
+				continue
+			}
+			alert := Alert{
+				ID:          generateAlertID(),
+				Type:        "threat_indicator",
+				Severity:    ind.Severity,
+				Title:       fmt.Sprintf("New %s indicator from %s", ind.Type, source.Name),
+				Description: ind.Value,
+				Source:      source.Name,
+				Timestamp:   time.Now(),
+				Metadata: map[string]interface{}{
+					"indicator_type":  ind.Type,
+					"indicator_value": ind.Value,
+					"tags":            ind.Tags,
+				},
+			}
+			if err := m.notifier.SendAlert(ctx, alert); err != nil {
+				return all, fmt.Errorf("send alert for indicator %s: %w", ind.Value, err)
+			}
+		}
+
+		all = append(all, indicators...)
+	}
+	return all, nil
+}
+
+func isHighSeverity(severity string) bool {
+	switch strings.ToLower(severity) {
+	case "high", "critical":
+		return true
+	default:
+		return false
+	}
 }
 
 // SendSecurityAlert sends security alerts to configured channels
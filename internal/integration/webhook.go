@@ -0,0 +1,399 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	signatureHeader       = "X-Worker-Signature"
+	defaultWebhookTimeout = 10 * time.Second
+
+	// integrationJobType is the retry job's type, mirroring the wire value
+	// of queue.JobTypeIntegration. It's spelled out here rather than
+	// imported so this package doesn't depend on internal/queue (which
+	// itself depends on internal/storage, which adapts WebhookLedger -
+	// importing queue here would be a cycle).
+	integrationJobType = "integration"
+
+	// responseSnippetLimit caps how much of a webhook's response body gets
+	// persisted in the ledger, so a misbehaving endpoint that echoes back
+	// megabytes can't blow up storage.
+	responseSnippetLimit = 2048
+)
+
+// JobEnqueuer is the minimal queue surface WebhookDeliverer needs to retry a
+// failed delivery through the worker's existing backoff/dead-letter
+// machinery, instead of rolling its own retry loop. jobType is the wire
+// value of a queue.JobType (integrationJobType for every call this package
+// makes); callers adapt *queue.Service to this interface rather than this
+// package importing internal/queue directly, the same way queue.Notifier is
+// defined locally instead of importing internal/integration.
+type JobEnqueuer interface {
+	Submit(ctx context.Context, jobType string, payload map[string]interface{}) error
+}
+
+// DeliveryAttempt records the outcome of one webhook delivery attempt, for
+// APIClient.DeliveryHistory.
+type DeliveryAttempt struct {
+	At           time.Time     `json:"at"`
+	StatusCode   int           `json:"status_code"`
+	Latency      time.Duration `json:"latency"`
+	Error        string        `json:"error,omitempty"`
+	ResponseBody string        `json:"response_body,omitempty"`
+}
+
+// WebhookLedger persists each delivery attempt for a webhook so
+// APIClient.DeliveryHistory can answer "what happened the last few times we
+// called this webhook". See internal/storage.NewWebhookLedgerStore for the
+// KV-backed implementation.
+type WebhookLedger interface {
+	RecordDelivery(ctx context.Context, webhookID string, attempt DeliveryAttempt) error
+	ListDeliveries(ctx context.Context, webhookID string, since time.Time) ([]DeliveryAttempt, error)
+}
+
+// WebhookDeliverer is the default APIClient: Subscribe/Unsubscribe manage a
+// set of webhooks, and SendAlert/DeliverJobCompletion (called for every
+// Alert the Manager raises, or a finished job matching a subscribed event)
+// sign the payload Stripe-style and POST it, falling back to the worker's
+// regular job retry machinery when the endpoint can't be reached.
+type WebhookDeliverer struct {
+	client *http.Client
+	jobs   JobEnqueuer
+	ledger WebhookLedger
+
+	mu       sync.RWMutex
+	webhooks map[string]WebhookConfig
+}
+
+// NewWebhookDeliverer constructs a WebhookDeliverer. jobs is where deliveries
+// that fail get retried through; ledger is where every attempt's outcome is
+// recorded.
+func NewWebhookDeliverer(jobs JobEnqueuer, ledger WebhookLedger) *WebhookDeliverer {
+	return &WebhookDeliverer{
+		client:   &http.Client{Timeout: defaultWebhookTimeout},
+		jobs:     jobs,
+		ledger:   ledger,
+		webhooks: make(map[string]WebhookConfig),
+	}
+}
+
+// Call performs a one-off HTTP request against an external API, independent
+// of the webhook subscriptions below.
+func (d *WebhookDeliverer) Call(ctx context.Context, config APIConfig, payload interface{}) (*APIResponse, error) {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("marshal request payload: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	method := config.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+	req, err := http.NewRequestWithContext(ctx, method, config.URL, body)
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range config.Headers {
+		req.Header.Set(k, v)
+	}
+
+	client := d.client
+	if config.Timeout > 0 {
+		c := *d.client
+		c.Timeout = config.Timeout
+		client = &c
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("call %s: %w", config.URL, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response from %s: %w", config.URL, err)
+	}
+
+	var decoded map[string]interface{}
+	_ = json.Unmarshal(data, &decoded)
+
+	headers := make(map[string]string, len(resp.Header))
+	for k := range resp.Header {
+		headers[k] = resp.Header.Get(k)
+	}
+
+	return &APIResponse{
+		StatusCode: resp.StatusCode,
+		Headers:    headers,
+		Body:       decoded,
+		Duration:   time.Since(start),
+	}, nil
+}
+
+// Subscribe registers config, keyed by config.ID, as a webhook to notify for
+// its Events. Subscribing the same ID again replaces the previous config.
+func (d *WebhookDeliverer) Subscribe(ctx context.Context, config WebhookConfig) error {
+	if config.ID == "" {
+		return fmt.Errorf("webhook config requires an ID")
+	}
+	if config.URL == "" {
+		return fmt.Errorf("webhook %s: URL is required", config.ID)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.webhooks[config.ID] = config
+	return nil
+}
+
+// Unsubscribe removes webhookID, if present.
+func (d *WebhookDeliverer) Unsubscribe(ctx context.Context, webhookID string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.webhooks, webhookID)
+	return nil
+}
+
+// SendAlert delivers alert to every enabled webhook subscribed to its Type
+// (or to the wildcard "*", matching every event), satisfying
+// Notifier so a Manager can use a WebhookDeliverer as its notifier.
+func (d *WebhookDeliverer) SendAlert(ctx context.Context, alert Alert) error {
+	return d.deliverEvent(ctx, alert.Type, alert)
+}
+
+// SendReport is not a webhook concept yet; it's a no-op so WebhookDeliverer
+// can still satisfy Notifier for callers that only care about alerts.
+func (d *WebhookDeliverer) SendReport(ctx context.Context, report Report, recipients []string) error {
+	return nil
+}
+
+// SendNotification is a no-op for the same reason as SendReport.
+func (d *WebhookDeliverer) SendNotification(ctx context.Context, notification Notification) error {
+	return nil
+}
+
+// DeliverJobCompletion notifies every webhook subscribed to eventName (e.g.
+// "job.completed") that a job finished, for callers that want subscribers
+// told about worker activity beyond Alerts. jobID and jobType identify the
+// job (jobType is the wire value of a queue.JobType); result is an
+// arbitrary caller-supplied summary of its outcome.
+func (d *WebhookDeliverer) DeliverJobCompletion(ctx context.Context, eventName, jobID, jobType string, result map[string]interface{}) error {
+	payload := map[string]interface{}{
+		"event":    eventName,
+		"job_id":   jobID,
+		"job_type": jobType,
+		"result":   result,
+	}
+	return d.deliverEvent(ctx, eventName, payload)
+}
+
+// deliverEvent signs and POSTs payload to every enabled webhook subscribed
+// to eventName, recording each attempt in the ledger. A webhook that can't
+// be reached (or answers outside 2xx) is retried through the job queue
+// rather than here, so a slow/down endpoint can't block the caller.
+func (d *WebhookDeliverer) deliverEvent(ctx context.Context, eventName string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal webhook payload: %w", err)
+	}
+
+	for _, wh := range d.subscribers(eventName) {
+		if err := d.deliverOnce(ctx, wh, body); err != nil {
+			if enqueueErr := d.scheduleRetry(ctx, wh.ID, body); enqueueErr != nil {
+				return fmt.Errorf("webhook %s: deliver: %v; retry: %w", wh.ID, err, enqueueErr)
+			}
+		}
+	}
+	return nil
+}
+
+// subscribers returns a snapshot of every enabled webhook whose Events
+// includes eventName or the wildcard "*".
+func (d *WebhookDeliverer) subscribers(eventName string) []WebhookConfig {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	var out []WebhookConfig
+	for _, wh := range d.webhooks {
+		if !wh.Enabled {
+			continue
+		}
+		for _, evt := range wh.Events {
+			if evt == eventName || evt == "*" {
+				out = append(out, wh)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// deliverOnce signs body and POSTs it to wh.URL, recording the attempt (success
+// or failure) in the ledger.
+func (d *WebhookDeliverer) deliverOnce(ctx context.Context, wh WebhookConfig, body []byte) error {
+	start := time.Now()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, wh.URL, bytes.NewReader(body))
+	if err != nil {
+		d.record(ctx, wh.ID, DeliveryAttempt{At: start, Error: err.Error()})
+		return fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range wh.Headers {
+		req.Header.Set(k, v)
+	}
+	if wh.Secret != "" {
+		req.Header.Set(signatureHeader, SignPayload(wh.Secret, body, time.Now()))
+	}
+
+	resp, err := d.client.Do(req)
+	latency := time.Since(start)
+	if err != nil {
+		d.record(ctx, wh.ID, DeliveryAttempt{At: start, Latency: latency, Error: err.Error()})
+		return fmt.Errorf("deliver to %s: %w", wh.URL, err)
+	}
+	defer resp.Body.Close()
+
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, responseSnippetLimit))
+	d.record(ctx, wh.ID, DeliveryAttempt{
+		At:           start,
+		StatusCode:   resp.StatusCode,
+		Latency:      latency,
+		ResponseBody: string(snippet),
+	})
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s: %s returned %d", wh.ID, wh.URL, resp.StatusCode)
+	}
+	return nil
+}
+
+func (d *WebhookDeliverer) record(ctx context.Context, webhookID string, attempt DeliveryAttempt) {
+	if d.ledger == nil {
+		return
+	}
+	if err := d.ledger.RecordDelivery(ctx, webhookID, attempt); err != nil {
+		// Best-effort: a ledger write failure shouldn't also fail (or
+		// retry) an otherwise-successful delivery.
+		_ = err
+	}
+}
+
+// scheduleRetry enqueues a JobTypeIntegration job carrying enough of the
+// original delivery to retry it later, leaning on Service.runJob's existing
+// exponential backoff and dead-letter handling rather than reimplementing
+// retry/backoff here.
+func (d *WebhookDeliverer) scheduleRetry(ctx context.Context, webhookID string, body []byte) error {
+	if d.jobs == nil {
+		return fmt.Errorf("no job queue configured to retry webhook %s", webhookID)
+	}
+	payload := map[string]interface{}{
+		"webhook_id": webhookID,
+		"body":       string(body),
+	}
+	return d.jobs.Submit(ctx, integrationJobType, payload)
+}
+
+// HandleRetryJob redelivers a webhook job enqueued by scheduleRetry, given
+// that job's Payload. Wire it up with
+// queueService.RegisterHandler(queue.JobTypeIntegration, "deliverWebhook",
+// queue.JobHandlerFunc(func(ctx context.Context, job queue.Job) error {
+// return deliverer.HandleRetryJob(ctx, job.Payload) })) to replace
+// Service's default processIntegration no-op. Returning an error here makes
+// Service.runJob apply its normal backoff and, past MaxRetry, dead-letter
+// the job - so repeated failures don't need their own retry counter.
+func (d *WebhookDeliverer) HandleRetryJob(ctx context.Context, payload map[string]interface{}) error {
+	webhookID, _ := payload["webhook_id"].(string)
+	body, _ := payload["body"].(string)
+
+	d.mu.RLock()
+	wh, ok := d.webhooks[webhookID]
+	d.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("webhook %s: no longer subscribed, dropping retry", webhookID)
+	}
+
+	return d.deliverOnce(ctx, wh, []byte(body))
+}
+
+// DeliveryHistory returns every delivery attempt recorded for webhookID at
+// or after since, for operators auditing why a subscriber says it missed an
+// event.
+func (d *WebhookDeliverer) DeliveryHistory(ctx context.Context, webhookID string, since time.Time) ([]DeliveryAttempt, error) {
+	if d.ledger == nil {
+		return nil, nil
+	}
+	return d.ledger.ListDeliveries(ctx, webhookID, since)
+}
+
+// SignPayload computes the value of the X-Worker-Signature header for body,
+// Stripe-style: the timestamp is folded into the signed string so a
+// captured header/body pair can't be replayed against a different request.
+func SignPayload(secret string, body []byte, at time.Time) string {
+	ts := at.Unix()
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(ts, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return fmt.Sprintf("t=%d,v1=%s", ts, hex.EncodeToString(mac.Sum(nil)))
+}
+
+// VerifySignature checks that header is a valid X-Worker-Signature value for
+// body under secret, and that its timestamp is within maxSkew of now -
+// rejecting both a tampered body and a stale, replayed header. Downstream
+// services consuming this worker's webhooks can import this package for
+// the same verification this worker expects of it.
+func VerifySignature(header, body, secret string, maxSkew time.Duration) error {
+	var ts int64
+	var sig string
+	for _, part := range strings.Split(header, ",") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "t":
+			parsed, err := strconv.ParseInt(kv[1], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid signature timestamp: %w", err)
+			}
+			ts = parsed
+		case "v1":
+			sig = kv[1]
+		}
+	}
+	if ts == 0 || sig == "" {
+		return fmt.Errorf("malformed signature header %q", header)
+	}
+
+	at := time.Unix(ts, 0)
+	if skew := time.Since(at); skew > maxSkew || skew < -maxSkew {
+		return fmt.Errorf("signature timestamp %s outside allowed skew of %s", at, maxSkew)
+	}
+
+	expected := SignPayload(secret, []byte(body), at)
+	expectedSig := expected[strings.Index(expected, "v1=")+len("v1="):]
+	if !hmac.Equal([]byte(sig), []byte(expectedSig)) {
+		return fmt.Errorf("signature mismatch")
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+package lock
+
+import "errors"
+
+// ErrLockHeld is returned by Manager.Acquire and AcquireWithRefresh when
+// name/key is already held by another owner and hasn't yet expired, so
+// callers can back off cleanly instead of treating it as a hard failure.
+var ErrLockHeld = errors.New("lock: already held by another owner")
+
+// ErrLeaseLost is returned by Lease.Renew when the lease's record no longer
+// matches what this process last wrote - another owner acquired it after
+// this lease expired, most likely because Renew wasn't called often enough
+// relative to its TTL. Lease.Release treats a lost lease as released and
+// returns nil instead.
+var ErrLeaseLost = errors.New("lock: lease lost to another owner")
@@ -0,0 +1,84 @@
+//go:build !noetcd
+
+package lock
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/client/v3/concurrency"
+)
+
+// EtcdLocker is a DistributedLocker backed by etcd's native lease-based
+// mutex (concurrency.Session/Mutex): the session's lease is kept alive by
+// etcd's own client-side keepalive, so this backend doesn't need its own
+// renew loop the way the KV- and Redis-backed lockers do.
+type EtcdLocker struct {
+	client *clientv3.Client
+}
+
+// NewEtcdLocker connects to the etcd cluster at endpoints.
+func NewEtcdLocker(endpoints []string) (*EtcdLocker, error) {
+	client, err := clientv3.New(clientv3.Config{Endpoints: endpoints})
+	if err != nil {
+		return nil, err
+	}
+	return &EtcdLocker{client: client}, nil
+}
+
+// Close releases the underlying etcd client.
+func (l *EtcdLocker) Close() error {
+	return l.client.Close()
+}
+
+// AcquireWithRefresh implements DistributedLocker for *EtcdLocker. ttl sets
+// the session's lease TTL in seconds (etcd leases are second-granularity);
+// etcd's client keeps that lease alive automatically for as long as the
+// session is open, so - unlike the other backends - there's no explicit
+// renew goroutine here, only one watching for the session to end (the
+// cluster became unreachable, or the lease genuinely expired).
+func (l *EtcdLocker) AcquireWithRefresh(ctx context.Context, name string, ttl time.Duration) (context.Context, context.CancelFunc, error) {
+	session, err := concurrency.NewSession(l.client, concurrency.WithTTL(int(ttl.Seconds())))
+	if err != nil {
+		return nil, nil, fmt.Errorf("lock: open session for %q: %w", name, err)
+	}
+
+	mutex := concurrency.NewMutex(session, lockKeyPrefix+name)
+	if err := mutex.TryLock(ctx); err != nil {
+		session.Close()
+		if err == concurrency.ErrLocked {
+			return nil, nil, ErrLockHeld
+		}
+		return nil, nil, fmt.Errorf("lock: acquire %q: %w", name, err)
+	}
+
+	lockCtx, cancel := context.WithCancel(ctx)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		select {
+		case <-lockCtx.Done():
+		case <-session.Done():
+			log.Printf("lock: etcd session holding lease %q ended", name)
+			cancel()
+		}
+	}()
+
+	release := func() {
+		cancel()
+		<-done
+
+		releaseCtx, releaseCancel := context.WithTimeout(context.Background(), releaseTimeout)
+		defer releaseCancel()
+		if err := mutex.Unlock(releaseCtx); err != nil {
+			log.Printf("lock: failed to release lease %q: %v", name, err)
+		}
+		session.Close()
+	}
+
+	return lockCtx, release, nil
+}
@@ -0,0 +1,96 @@
+package lock
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/awesomeapibrasil/gateway-worker/internal/storage"
+)
+
+// Lease is a held lock on one name, granted to exactly one owner until it
+// expires, is Renew'd, or is Release'd.
+type Lease struct {
+	manager *Manager
+	name    string
+	owner   string
+	ttl     time.Duration
+}
+
+// Renew extends the lease by its original TTL, provided this process still
+// holds it. It returns ErrLeaseLost if the stored record's owner no longer
+// matches - the lease expired before this call and another replica
+// acquired it.
+func (l *Lease) Renew(ctx context.Context) error {
+	current, existing, err := l.loadOwned(ctx)
+	if err != nil {
+		return err
+	}
+
+	newData, err := json.Marshal(record{Owner: l.owner, ExpiresAt: time.Now().Add(l.ttl)})
+	if err != nil {
+		return fmt.Errorf("lock: encode lease: %w", err)
+	}
+
+	ok, err := l.manager.kv.CompareAndSwap(ctx, lockKey(l.name), current, newData)
+	if err != nil {
+		return fmt.Errorf("lock: renew %q: %w", l.name, err)
+	}
+	if !ok {
+		return ErrLeaseLost
+	}
+	_ = existing
+	return nil
+}
+
+// Release gives up the lease early by marking it expired, so the next
+// Acquire for name doesn't have to wait out the remaining TTL. It is a
+// no-op (returns nil) if the lease was already lost to another owner.
+func (l *Lease) Release(ctx context.Context) error {
+	current, _, err := l.loadOwned(ctx)
+	if err == ErrLeaseLost {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	expired, err := json.Marshal(record{Owner: l.owner, ExpiresAt: time.Unix(0, 0)})
+	if err != nil {
+		return fmt.Errorf("lock: encode lease: %w", err)
+	}
+
+	ok, err := l.manager.kv.CompareAndSwap(ctx, lockKey(l.name), current, expired)
+	if err != nil {
+		return fmt.Errorf("lock: release %q: %w", l.name, err)
+	}
+	if !ok {
+		// Lost the race with someone stealing an already-expired lease;
+		// either way, we no longer hold it.
+		return nil
+	}
+	return nil
+}
+
+// loadOwned reads the current stored record for the lease and confirms it
+// is still owned by l, returning the raw bytes (for use as CompareAndSwap's
+// oldValue) alongside the decoded record.
+func (l *Lease) loadOwned(ctx context.Context) ([]byte, record, error) {
+	current, err := l.manager.kv.Get(ctx, lockKey(l.name))
+	if err != nil {
+		if err == storage.ErrNotFound {
+			return nil, record{}, ErrLeaseLost
+		}
+		return nil, record{}, fmt.Errorf("lock: read %q: %w", l.name, err)
+	}
+
+	var existing record
+	if err := json.Unmarshal(current, &existing); err != nil {
+		return nil, record{}, fmt.Errorf("lock: decode lease for %q: %w", l.name, err)
+	}
+	if existing.Owner != l.owner {
+		return nil, record{}, ErrLeaseLost
+	}
+	return current, existing, nil
+}
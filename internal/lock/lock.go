@@ -0,0 +1,116 @@
+// Package lock implements a distributed, lease-based mutual-exclusion lock
+// on top of the shared storage.KV abstraction, so maintenance jobs (cleanup
+// sweeps, backups, schema migrations) run on exactly one worker replica at
+// a time instead of every replica racing to do the same work.
+package lock
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/awesomeapibrasil/gateway-worker/internal/storage"
+)
+
+const lockKeyPrefix = "lock/"
+
+// record is what's actually stored in the KV for a held lock.
+type record struct {
+	Owner     string    `json:"owner"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (r record) expired() bool {
+	return time.Now().After(r.ExpiresAt)
+}
+
+func lockKey(name string) string {
+	return lockKeyPrefix + name
+}
+
+// DistributedLocker grants mutual exclusion on a named key across every
+// replica that shares the same backing store, auto-refreshing the lock in
+// the background for as long as it's held. Implementations: *Manager
+// (storage.KV-backed - suitable wherever a KV driver is already configured),
+// and the Postgres advisory-lock, Redis Redlock-style, and etcd
+// lease-mutex backends in postgres.go, redis.go, and etcd.go.
+type DistributedLocker interface {
+	// AcquireWithRefresh acquires key for ttl, or returns ErrLockHeld if
+	// another owner already holds it. On success it returns a context
+	// derived from ctx that is cancelled the moment the lock is lost (its
+	// TTL lapsed without a successful renew - e.g. the backing store
+	// became unreachable), and a release func that cancels that context,
+	// stops the background refresh goroutine, and releases the lock.
+	// release must always be called, typically via defer; because the
+	// returned context (and so the refresh goroutine) is derived from ctx,
+	// forgetting to call it leaks the goroutine for at most ctx's own
+	// remaining lifetime, not indefinitely.
+	AcquireWithRefresh(ctx context.Context, key string, ttl time.Duration) (context.Context, context.CancelFunc, error)
+}
+
+// Manager grants Leases on named locks backed by kv. Every worker replica
+// sharing the same kv backend contends for the same locks.
+type Manager struct {
+	kv storage.KV
+}
+
+// New builds a Manager backed by kv.
+func New(kv storage.KV) *Manager {
+	return &Manager{kv: kv}
+}
+
+// Acquire grants a Lease on name valid for ttl, or ErrLockHeld if another
+// owner already holds an unexpired lease on it. A held lease whose TTL has
+// elapsed (its owner crashed or hung) is treated as free and reassigned.
+func (m *Manager) Acquire(ctx context.Context, name string, ttl time.Duration) (*Lease, error) {
+	key := lockKey(name)
+	owner := generateOwnerID()
+	newData, err := json.Marshal(record{Owner: owner, ExpiresAt: time.Now().Add(ttl)})
+	if err != nil {
+		return nil, fmt.Errorf("lock: encode lease: %w", err)
+	}
+
+	current, err := m.kv.Get(ctx, key)
+	switch {
+	case err == storage.ErrNotFound:
+		ok, err := m.kv.CompareAndSwap(ctx, key, nil, newData)
+		if err != nil {
+			return nil, fmt.Errorf("lock: acquire %q: %w", name, err)
+		}
+		if !ok {
+			return nil, ErrLockHeld
+		}
+	case err != nil:
+		return nil, fmt.Errorf("lock: read %q: %w", name, err)
+	default:
+		var existing record
+		if err := json.Unmarshal(current, &existing); err != nil {
+			return nil, fmt.Errorf("lock: decode existing lease for %q: %w", name, err)
+		}
+		if !existing.expired() {
+			return nil, ErrLockHeld
+		}
+
+		ok, err := m.kv.CompareAndSwap(ctx, key, current, newData)
+		if err != nil {
+			return nil, fmt.Errorf("lock: steal expired lease %q: %w", name, err)
+		}
+		if !ok {
+			// Another replica won the race to steal it first.
+			return nil, ErrLockHeld
+		}
+	}
+
+	return &Lease{manager: m, name: name, owner: owner, ttl: ttl}, nil
+}
+
+func generateOwnerID() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Sprintf("owner-%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,102 @@
+//go:build !nopostgres
+
+package lock
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresLocker is a DistributedLocker backed by Postgres advisory locks:
+// pg_try_advisory_lock is held on a single dedicated connection for as long
+// as the lock is held, so Postgres itself releases it the moment that
+// connection (and so this replica) dies - no TTL or manual expiry
+// bookkeeping needed, unlike the KV- and Redis-backed lockers.
+type PostgresLocker struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresLocker connects to the Postgres instance named by dsn.
+func NewPostgresLocker(ctx context.Context, dsn string) (*PostgresLocker, error) {
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &PostgresLocker{pool: pool}, nil
+}
+
+// Close releases the underlying connection pool.
+func (l *PostgresLocker) Close() {
+	l.pool.Close()
+}
+
+// advisoryKey hashes name to the int64 pg_try_advisory_lock takes, since
+// Postgres advisory locks are keyed by number rather than by string.
+func advisoryKey(name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	return int64(h.Sum64())
+}
+
+// AcquireWithRefresh implements DistributedLocker for *PostgresLocker. ttl
+// bounds the period between the background goroutine's connection-health
+// pings (ttl/renewFraction), for parity with the other backends' signature
+// - it doesn't bound the lock itself, since the advisory lock lives exactly
+// as long as the dedicated connection holding it does, and Postgres
+// releases it the instant that connection drops.
+func (l *PostgresLocker) AcquireWithRefresh(ctx context.Context, name string, ttl time.Duration) (context.Context, context.CancelFunc, error) {
+	conn, err := l.pool.Acquire(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("lock: acquire connection for %q: %w", name, err)
+	}
+
+	var locked bool
+	if err := conn.QueryRow(ctx, "SELECT pg_try_advisory_lock($1)", advisoryKey(name)).Scan(&locked); err != nil {
+		conn.Release()
+		return nil, nil, fmt.Errorf("lock: acquire %q: %w", name, err)
+	}
+	if !locked {
+		conn.Release()
+		return nil, nil, ErrLockHeld
+	}
+
+	lockCtx, cancel := context.WithCancel(ctx)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(ttl / renewFraction)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-lockCtx.Done():
+				return
+			case <-ticker.C:
+				if err := conn.Ping(lockCtx); err != nil {
+					log.Printf("lock: connection holding lease %q died: %v", name, err)
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	release := func() {
+		cancel()
+		<-done
+
+		releaseCtx, releaseCancel := context.WithTimeout(context.Background(), releaseTimeout)
+		defer releaseCancel()
+		if _, err := conn.Exec(releaseCtx, "SELECT pg_advisory_unlock($1)", advisoryKey(name)); err != nil {
+			log.Printf("lock: failed to release lease %q: %v", name, err)
+		}
+		conn.Release()
+	}
+
+	return lockCtx, release, nil
+}
@@ -0,0 +1,113 @@
+//go:build !noredis
+
+package lock
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisLocker is a DistributedLocker backed by a single Redis instance,
+// using the Redlock recipe's single-node primitive: SET key owner NX PX ttl
+// to acquire, with Lua-guarded renew/release so a replica only ever
+// extends or clears a key it still owns - the same compare-and-swap
+// discipline internal/queue's RedisBackend uses for its leader election.
+type RedisLocker struct {
+	client *redis.Client
+	owner  string
+}
+
+// NewRedisLocker connects to the Redis instance named by dsn (a redis://
+// URL, as accepted by redis.ParseURL).
+func NewRedisLocker(ctx context.Context, dsn string) (*RedisLocker, error) {
+	opts, err := redis.ParseURL(dsn)
+	if err != nil {
+		return nil, err
+	}
+	client := redis.NewClient(opts)
+	if err := client.Ping(ctx).Err(); err != nil {
+		return nil, err
+	}
+	return &RedisLocker{client: client, owner: generateOwnerID()}, nil
+}
+
+// Close releases the underlying Redis connection.
+func (l *RedisLocker) Close() error {
+	return l.client.Close()
+}
+
+func (l *RedisLocker) redisKey(name string) string {
+	return lockKeyPrefix + name
+}
+
+// redisRenewScript extends key's TTL only if it's still held by owner, so a
+// renew racing a concurrent steal-after-expiry can't resurrect a lock this
+// replica no longer actually holds.
+const redisRenewScript = `
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	redis.call("PEXPIRE", KEYS[1], ARGV[2])
+	return 1
+end
+return 0
+`
+
+// redisReleaseScript deletes key only if it's still held by owner, mirroring
+// redisRenewScript's ownership check.
+const redisReleaseScript = `
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	redis.call("DEL", KEYS[1])
+	return 1
+end
+return 0
+`
+
+// AcquireWithRefresh implements DistributedLocker for *RedisLocker.
+func (l *RedisLocker) AcquireWithRefresh(ctx context.Context, name string, ttl time.Duration) (context.Context, context.CancelFunc, error) {
+	key := l.redisKey(name)
+	ok, err := l.client.SetNX(ctx, key, l.owner, ttl).Result()
+	if err != nil {
+		return nil, nil, fmt.Errorf("lock: acquire %q: %w", name, err)
+	}
+	if !ok {
+		return nil, nil, ErrLockHeld
+	}
+
+	lockCtx, cancel := context.WithCancel(ctx)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(ttl / renewFraction)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-lockCtx.Done():
+				return
+			case <-ticker.C:
+				renewed, err := l.client.Eval(ctx, redisRenewScript, []string{key}, l.owner, ttl.Milliseconds()).Int()
+				if err != nil || renewed == 0 {
+					log.Printf("lock: failed to renew lease %q: %v", name, err)
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	release := func() {
+		cancel()
+		<-done
+
+		releaseCtx, releaseCancel := context.WithTimeout(context.Background(), releaseTimeout)
+		defer releaseCancel()
+		if err := l.client.Eval(releaseCtx, redisReleaseScript, []string{key}, l.owner).Err(); err != nil {
+			log.Printf("lock: failed to release lease %q: %v", name, err)
+		}
+	}
+
+	return lockCtx, release, nil
+}
@@ -0,0 +1,89 @@
+package lock
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// renewFraction divides a lease's TTL to give the interval at which the
+// background refresh loop renews it (ttl/renewFraction), leaving headroom for
+// a missed tick (e.g. a slow KV backend) to still renew before the lease
+// actually expires.
+const renewFraction = 3
+
+// releaseTimeout bounds the final Release call AcquireWithRefresh's cancel
+// func issues once the caller is done, independent of the ctx it was given
+// (which may already be cancelled by then).
+const releaseTimeout = 5 * time.Second
+
+// AcquireWithRefresh implements DistributedLocker for *Manager: it acquires
+// name via Acquire and renews it in the background at ttl/renewFraction
+// intervals until the returned cancel func is called.
+func (m *Manager) AcquireWithRefresh(ctx context.Context, name string, ttl time.Duration) (context.Context, context.CancelFunc, error) {
+	lease, err := m.Acquire(ctx, name, ttl)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	lockCtx, cancel := context.WithCancel(ctx)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(ttl / renewFraction)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-lockCtx.Done():
+				return
+			case <-ticker.C:
+				if err := lease.Renew(lockCtx); err != nil {
+					log.Printf("lock: failed to renew lease %q: %v", name, err)
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	release := func() {
+		cancel()
+		<-done
+
+		releaseCtx, releaseCancel := context.WithTimeout(context.Background(), releaseTimeout)
+		defer releaseCancel()
+		if err := lease.Release(releaseCtx); err != nil {
+			log.Printf("lock: failed to release lease %q: %v", name, err)
+		}
+	}
+
+	return lockCtx, release, nil
+}
+
+// RunExclusive acquires name (with the given ttl) from locker and runs fn
+// while holding it, via AcquireWithRefresh so the lease is kept alive for as
+// long as fn runs. It returns ErrLockHeld without running fn if another
+// replica already holds the lock - callers should treat that as "someone
+// else is handling this run" rather than an error. If the lock is lost
+// while fn runs (the refresh goroutine gave up and cancelled the context fn
+// was given), RunExclusive reports ErrLockHeld alongside whatever error fn
+// returned.
+func RunExclusive(ctx context.Context, locker DistributedLocker, name string, ttl time.Duration, fn func(context.Context) error) error {
+	lockCtx, release, err := locker.AcquireWithRefresh(ctx, name, ttl)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	fnErr := fn(lockCtx)
+
+	if lockCtx.Err() != nil && ctx.Err() == nil {
+		if fnErr != nil {
+			return fmt.Errorf("%w (lock lost mid-run: %v)", fnErr, ErrLockHeld)
+		}
+		return fmt.Errorf("lock: %q lost mid-run: %w", name, ErrLockHeld)
+	}
+	return fnErr
+}
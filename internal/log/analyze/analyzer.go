@@ -0,0 +1,214 @@
+// Package analyze implements log.Analyzer: traffic pattern statistics and
+// Sigma-style threat detection (see internal/log/sigma) plus a small
+// rate-based check for the one common attack pattern Sigma's per-event
+// model can't express - a single client racking up an abnormal share of
+// errors.
+package analyze
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/awesomeapibrasil/gateway-worker/internal/log"
+	"github.com/awesomeapibrasil/gateway-worker/internal/log/sigma"
+)
+
+// errorRateThreshold is the fraction of a single IP's requests that must
+// be 4xx/5xx, above minRequestsForRateCheck total requests, before
+// DetectThreats flags it as a high-error-rate client.
+const (
+	errorRateThreshold      = 0.5
+	minRequestsForRateCheck = 20
+)
+
+// Analyzer implements log.Analyzer.
+type Analyzer struct {
+	rules []sigma.Rule
+}
+
+// New builds an Analyzer evaluating rules against every entry passed to
+// DetectThreats.
+func New(rules []sigma.Rule) *Analyzer {
+	return &Analyzer{rules: rules}
+}
+
+// NewDefault builds an Analyzer using DefaultRules.
+func NewDefault() *Analyzer {
+	return New(DefaultRules())
+}
+
+// AnalyzeTraffic computes request/IP/path/status-code/latency statistics
+// over entries.
+func (a *Analyzer) AnalyzeTraffic(ctx context.Context, entries []log.LogEntry) (*log.TrafficAnalysis, error) {
+	if len(entries) == 0 {
+		return &log.TrafficAnalysis{}, nil
+	}
+
+	ips := make(map[string]struct{})
+	pathCounts := make(map[string]int)
+	pathDurations := make(map[string]time.Duration)
+	statusCounts := make(map[int]int)
+	durations := make([]time.Duration, 0, len(entries))
+
+	minTS, maxTS := entries[0].Timestamp, entries[0].Timestamp
+	for _, e := range entries {
+		if e.ClientIP != "" {
+			ips[e.ClientIP] = struct{}{}
+		}
+		pathCounts[e.Path]++
+		pathDurations[e.Path] += e.ResponseTime
+		statusCounts[e.StatusCode]++
+		durations = append(durations, e.ResponseTime)
+
+		if e.Timestamp.Before(minTS) {
+			minTS = e.Timestamp
+		}
+		if e.Timestamp.After(maxTS) {
+			maxTS = e.Timestamp
+		}
+	}
+
+	return &log.TrafficAnalysis{
+		Period:        maxTS.Sub(minTS),
+		TotalRequests: len(entries),
+		UniqueIPs:     len(ips),
+		TopPaths:      topPaths(pathCounts, pathDurations),
+		StatusCodes:   statusStats(statusCounts),
+		ResponseTimes: responseTimeStats(durations),
+	}, nil
+}
+
+// DetectThreats evaluates every Sigma rule against every entry, then checks
+// for clients with an abnormally high error rate.
+func (a *Analyzer) DetectThreats(ctx context.Context, entries []log.LogEntry) ([]log.ThreatAlert, error) {
+	var alerts []log.ThreatAlert
+
+	for _, entry := range entries {
+		for _, rule := range a.rules {
+			matched, err := rule.Matches(entry)
+			if err != nil {
+				return nil, fmt.Errorf("analyze: rule %q: %w", rule.ID, err)
+			}
+			if !matched {
+				continue
+			}
+			alerts = append(alerts, log.ThreatAlert{
+				Type:        rule.ID,
+				Severity:    rule.Level,
+				Description: rule.Description,
+				Source:      entry.Source,
+				Timestamp:   entry.Timestamp,
+				Details: map[string]interface{}{
+					"title":      rule.Title,
+					"client_ip":  entry.ClientIP,
+					"path":       entry.Path,
+					"request_id": entry.RequestID,
+				},
+			})
+		}
+	}
+
+	alerts = append(alerts, highErrorRateAlerts(entries)...)
+	return alerts, nil
+}
+
+func highErrorRateAlerts(entries []log.LogEntry) []log.ThreatAlert {
+	type counts struct{ total, errors int }
+	byIP := make(map[string]*counts)
+
+	for _, e := range entries {
+		if e.ClientIP == "" {
+			continue
+		}
+		c, ok := byIP[e.ClientIP]
+		if !ok {
+			c = &counts{}
+			byIP[e.ClientIP] = c
+		}
+		c.total++
+		if e.StatusCode >= 400 {
+			c.errors++
+		}
+	}
+
+	var alerts []log.ThreatAlert
+	for ip, c := range byIP {
+		if c.total < minRequestsForRateCheck {
+			continue
+		}
+		rate := float64(c.errors) / float64(c.total)
+		if rate < errorRateThreshold {
+			continue
+		}
+		alerts = append(alerts, log.ThreatAlert{
+			Type:        "high-error-rate-client",
+			Severity:    "medium",
+			Description: "Client is generating an abnormally high share of 4xx/5xx responses.",
+			Source:      ip,
+			Timestamp:   time.Now(),
+			Details: map[string]interface{}{
+				"client_ip":  ip,
+				"total":      c.total,
+				"errors":     c.errors,
+				"error_rate": rate,
+			},
+		})
+	}
+	return alerts
+}
+
+func topPaths(counts map[string]int, durations map[string]time.Duration) []log.PathStat {
+	stats := make([]log.PathStat, 0, len(counts))
+	for path, count := range counts {
+		avg := time.Duration(0)
+		if count > 0 {
+			avg = durations[path] / time.Duration(count)
+		}
+		stats = append(stats, log.PathStat{Path: path, Count: count, AvgTime: avg})
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Count > stats[j].Count })
+	return stats
+}
+
+func statusStats(counts map[int]int) []log.StatusStat {
+	stats := make([]log.StatusStat, 0, len(counts))
+	for code, count := range counts {
+		stats = append(stats, log.StatusStat{Code: code, Count: count})
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Code < stats[j].Code })
+	return stats
+}
+
+func responseTimeStats(durations []time.Duration) log.ResponseTimeStats {
+	if len(durations) == 0 {
+		return log.ResponseTimeStats{}
+	}
+
+	sorted := append([]time.Duration(nil), durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var sum time.Duration
+	for _, d := range sorted {
+		sum += d
+	}
+
+	return log.ResponseTimeStats{
+		Mean: sum / time.Duration(len(sorted)),
+		P50:  percentile(sorted, 0.50),
+		P95:  percentile(sorted, 0.95),
+		P99:  percentile(sorted, 0.99),
+	}
+}
+
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(p * float64(len(sorted)))
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return sorted[idx]
+}
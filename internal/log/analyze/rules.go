@@ -0,0 +1,81 @@
+package analyze
+
+import "github.com/awesomeapibrasil/gateway-worker/internal/log/sigma"
+
+// DefaultRules returns the built-in Sigma-style rule set covering common
+// web attack signatures. Callers can pass additional or replacement rules
+// to New.
+func DefaultRules() []sigma.Rule {
+	return []sigma.Rule{
+		{
+			ID:          "sql-injection-attempt",
+			Title:       "Possible SQL injection attempt",
+			Description: "Request path or query contains common SQL injection payloads.",
+			Level:       "high",
+			Detection: sigma.Detection{
+				Selections: map[string]sigma.Selection{
+					"selection": {
+						{Field: "path", Type: sigma.MatchRegex, Pattern: `(?i)(union(\s+all)?\s+select|or\s+1\s*=\s*1|'\s*or\s*'1'\s*=\s*'1|;\s*drop\s+table)`},
+					},
+				},
+				Condition: "selection",
+			},
+		},
+		{
+			ID:          "path-traversal-attempt",
+			Title:       "Possible path traversal attempt",
+			Description: "Request path contains directory traversal sequences.",
+			Level:       "high",
+			Detection: sigma.Detection{
+				Selections: map[string]sigma.Selection{
+					"selection": {
+						{Field: "path", Type: sigma.MatchRegex, Pattern: `(?i)(\.\./|\.\.%2f|%2e%2e%2f)`},
+					},
+				},
+				Condition: "selection",
+			},
+		},
+		{
+			ID:          "sensitive-path-scan",
+			Title:       "Scan for sensitive or administrative paths",
+			Description: "Request targets a path commonly probed by vulnerability scanners.",
+			Level:       "medium",
+			Detection: sigma.Detection{
+				Selections: map[string]sigma.Selection{
+					"selection": {
+						{Field: "path", Type: sigma.MatchRegex, Pattern: `(?i)^/(\.env|\.git/|wp-admin|wp-login\.php|phpmyadmin|\.aws/credentials)`},
+					},
+				},
+				Condition: "selection",
+			},
+		},
+		{
+			ID:          "known-scanner-user-agent",
+			Title:       "Known vulnerability scanner user agent",
+			Description: "User-Agent matches a well-known scanning or exploitation tool.",
+			Level:       "medium",
+			Detection: sigma.Detection{
+				Selections: map[string]sigma.Selection{
+					"selection": {
+						{Field: "user_agent", Type: sigma.MatchRegex, Pattern: `(?i)(sqlmap|nikto|nmap|nessus|acunetix|masscan)`},
+					},
+				},
+				Condition: "selection",
+			},
+		},
+		{
+			ID:          "command-injection-attempt",
+			Title:       "Possible command injection attempt",
+			Description: "Request path contains shell metacharacters commonly used to chain commands.",
+			Level:       "high",
+			Detection: sigma.Detection{
+				Selections: map[string]sigma.Selection{
+					"selection": {
+						{Field: "path", Type: sigma.MatchRegex, Pattern: `(?i)(;|\||&&|\$\(|` + "`" + `)\s*(cat|ls|wget|curl|nc|bash|sh)\b`},
+					},
+				},
+				Condition: "selection",
+			},
+		},
+	}
+}
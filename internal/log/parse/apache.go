@@ -0,0 +1,76 @@
+package parse
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/awesomeapibrasil/gateway-worker/internal/log"
+)
+
+// clfTimestamp is the timestamp layout both Apache and Nginx combined log
+// format use: "02/Jan/2006:15:04:05 -0700".
+const clfTimestamp = "02/Jan/2006:15:04:05 -0700"
+
+// apacheCombined matches the Apache/NCSA "combined" log format:
+//
+//	host ident authuser [date] "request" status bytes "referer" "user-agent"
+var apacheCombined = regexp.MustCompile(`^(\S+) \S+ (\S+) \[([^\]]+)\] "(\S+) (\S+)[^"]*" (\d{3}) (\S+) "([^"]*)" "([^"]*)"$`)
+
+// Apache parses the Apache/NCSA combined log format.
+type Apache struct{}
+
+// NewApache builds an Apache combined-log-format parser.
+func NewApache() *Apache {
+	return &Apache{}
+}
+
+// Parse matches raw against the combined log format.
+func (a *Apache) Parse(ctx context.Context, raw string) (*log.LogEntry, error) {
+	m := apacheCombined.FindStringSubmatch(raw)
+	if m == nil {
+		return nil, fmt.Errorf("parse: apache: line does not match combined log format")
+	}
+
+	ts, err := time.Parse(clfTimestamp, m[3])
+	if err != nil {
+		return nil, fmt.Errorf("parse: apache: timestamp: %w", err)
+	}
+	status, _ := strconv.Atoi(m[6])
+
+	entry := &log.LogEntry{
+		Timestamp:  ts,
+		Message:    raw,
+		ClientIP:   m[1],
+		Method:     m[4],
+		Path:       m[5],
+		StatusCode: status,
+		UserAgent:  m[9],
+		Headers: map[string]string{
+			"Referer": m[8],
+		},
+		Metadata: map[string]interface{}{
+			"auth_user":  m[2],
+			"bytes_sent": parseSizeField(m[7]),
+			"access_log": "apache",
+		},
+	}
+	return entry, nil
+}
+
+// Enrich applies the shared enrichment common to every format.
+func (a *Apache) Enrich(ctx context.Context, entry *log.LogEntry) error {
+	return enrichCommon(ctx, entry)
+}
+
+// parseSizeField parses the combined log format's bytes-sent field, which
+// is "-" when the server recorded no response body.
+func parseSizeField(raw string) int64 {
+	if raw == "-" {
+		return 0
+	}
+	n, _ := strconv.ParseInt(raw, 10, 64)
+	return n
+}
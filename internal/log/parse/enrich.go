@@ -0,0 +1,55 @@
+package parse
+
+import (
+	"context"
+	"strings"
+
+	"github.com/awesomeapibrasil/gateway-worker/internal/log"
+)
+
+// enrichCommon fills in fields every format's Enrich shares: a Level
+// derived from StatusCode when the parser didn't set one, and a coarse
+// client classification (bot/browser/unknown) from UserAgent. Format-
+// specific Enrich implementations call this and then add their own.
+func enrichCommon(ctx context.Context, entry *log.LogEntry) error {
+	if entry.Level == "" {
+		entry.Level = levelForStatus(entry.StatusCode)
+	}
+
+	if entry.Metadata == nil {
+		entry.Metadata = make(map[string]interface{})
+	}
+	entry.Metadata["client_class"] = classifyUserAgent(entry.UserAgent)
+	return nil
+}
+
+func levelForStatus(status int) string {
+	switch {
+	case status >= 500:
+		return "error"
+	case status >= 400:
+		return "warn"
+	case status == 0:
+		return "info"
+	default:
+		return "info"
+	}
+}
+
+// knownBots are substrings of User-Agent strings that identify the request
+// as automated rather than a browser; this is a coarse heuristic, not a
+// full device-detection library.
+var knownBots = []string{"bot", "crawler", "spider", "curl", "wget", "python-requests", "sqlmap", "nikto", "nmap"}
+
+func classifyUserAgent(ua string) string {
+	if ua == "" {
+		return "unknown"
+	}
+	lower := strings.ToLower(ua)
+	for _, marker := range knownBots {
+		if strings.Contains(lower, marker) {
+			return "bot"
+		}
+	}
+	return "browser"
+}
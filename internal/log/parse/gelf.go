@@ -0,0 +1,82 @@
+package parse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/awesomeapibrasil/gateway-worker/internal/log"
+)
+
+// GELF parses the Graylog Extended Log Format
+// (https://go2docs.graylog.org/current/getting_in_log_data/gelf.html): a
+// JSON object with a handful of fixed fields plus arbitrary "_"-prefixed
+// custom ones.
+type GELF struct{}
+
+// NewGELF builds a GELF parser.
+func NewGELF() *GELF {
+	return &GELF{}
+}
+
+type gelfMessage struct {
+	Version      string                 `json:"version"`
+	Host         string                 `json:"host"`
+	ShortMessage string                 `json:"short_message"`
+	FullMessage  string                 `json:"full_message"`
+	Timestamp    float64                `json:"timestamp"`
+	Level        int                    `json:"level"`
+	Extra        map[string]interface{} `json:"-"`
+}
+
+// syslogLevelNames maps GELF's syslog severity levels to log.LogEntry's
+// string Level field.
+var syslogLevelNames = map[int]string{
+	0: "emerg", 1: "alert", 2: "crit", 3: "error",
+	4: "warn", 5: "notice", 6: "info", 7: "debug",
+}
+
+// Parse decodes raw as a single GELF message. version is required, per the
+// GELF spec, and is what distinguishes a GELF line from a plain JSON one
+// for Auto.
+func (g *GELF) Parse(ctx context.Context, raw string) (*log.LogEntry, error) {
+	var generic map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &generic); err != nil {
+		return nil, fmt.Errorf("parse: gelf: %w", err)
+	}
+	if _, ok := generic["version"]; !ok {
+		return nil, fmt.Errorf("parse: gelf: missing required \"version\" field")
+	}
+
+	var msg gelfMessage
+	if err := json.Unmarshal([]byte(raw), &msg); err != nil {
+		return nil, fmt.Errorf("parse: gelf: %w", err)
+	}
+
+	metadata := make(map[string]interface{}, len(generic))
+	for k, v := range generic {
+		if strings.HasPrefix(k, "_") {
+			metadata[strings.TrimPrefix(k, "_")] = v
+		}
+	}
+
+	message := msg.ShortMessage
+	if message == "" {
+		message = msg.FullMessage
+	}
+
+	return &log.LogEntry{
+		Timestamp: time.Unix(0, int64(msg.Timestamp*float64(time.Second))),
+		Level:     syslogLevelNames[msg.Level],
+		Source:    msg.Host,
+		Message:   message,
+		Metadata:  metadata,
+	}, nil
+}
+
+// Enrich applies the shared enrichment common to every format.
+func (g *GELF) Enrich(ctx context.Context, entry *log.LogEntry) error {
+	return enrichCommon(ctx, entry)
+}
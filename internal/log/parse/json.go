@@ -0,0 +1,96 @@
+package parse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/awesomeapibrasil/gateway-worker/internal/log"
+)
+
+// JSON parses one-object-per-line structured access logs, the format the
+// gateway itself emits. Field names follow log.LogEntry's json tags, with a
+// few common aliases accepted for logs produced by other tooling.
+type JSON struct{}
+
+// NewJSON builds a JSON parser.
+func NewJSON() *JSON {
+	return &JSON{}
+}
+
+type jsonLogLine struct {
+	Timestamp    *time.Time             `json:"timestamp"`
+	Time         *time.Time             `json:"time"`
+	Level        string                 `json:"level"`
+	Source       string                 `json:"source"`
+	Message      string                 `json:"message"`
+	Msg          string                 `json:"msg"`
+	RequestID    string                 `json:"request_id"`
+	Method       string                 `json:"method"`
+	Path         string                 `json:"path"`
+	StatusCode   int                    `json:"status_code"`
+	Status       int                    `json:"status"`
+	ResponseTime time.Duration          `json:"response_time"`
+	ClientIP     string                 `json:"client_ip"`
+	UserAgent    string                 `json:"user_agent"`
+	Headers      map[string]string      `json:"headers"`
+	Metadata     map[string]interface{} `json:"metadata"`
+}
+
+// Parse decodes raw as a single JSON log line.
+func (j *JSON) Parse(ctx context.Context, raw string) (*log.LogEntry, error) {
+	var line jsonLogLine
+	if err := json.Unmarshal([]byte(raw), &line); err != nil {
+		return nil, fmt.Errorf("parse: json: %w", err)
+	}
+
+	entry := &log.LogEntry{
+		Level:        line.Level,
+		Source:       line.Source,
+		Message:      firstNonEmpty(line.Message, line.Msg),
+		RequestID:    line.RequestID,
+		Method:       line.Method,
+		Path:         line.Path,
+		StatusCode:   firstNonZero(line.StatusCode, line.Status),
+		ResponseTime: line.ResponseTime,
+		ClientIP:     line.ClientIP,
+		UserAgent:    line.UserAgent,
+		Headers:      line.Headers,
+		Metadata:     line.Metadata,
+	}
+
+	switch {
+	case line.Timestamp != nil:
+		entry.Timestamp = *line.Timestamp
+	case line.Time != nil:
+		entry.Timestamp = *line.Time
+	default:
+		entry.Timestamp = time.Now()
+	}
+
+	return entry, nil
+}
+
+// Enrich applies the shared enrichment common to every format.
+func (j *JSON) Enrich(ctx context.Context, entry *log.LogEntry) error {
+	return enrichCommon(ctx, entry)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func firstNonZero(values ...int) int {
+	for _, v := range values {
+		if v != 0 {
+			return v
+		}
+	}
+	return 0
+}
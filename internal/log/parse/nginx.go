@@ -0,0 +1,66 @@
+package parse
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/awesomeapibrasil/gateway-worker/internal/log"
+)
+
+// nginxCombined matches Nginx's default "combined" format extended with the
+// $request_time variable nearly every gateway-fronting Nginx config appends
+// to it, which is what distinguishes it from apacheCombined for Auto.
+var nginxCombined = regexp.MustCompile(`^(\S+) \S+ (\S+) \[([^\]]+)\] "(\S+) (\S+)[^"]*" (\d{3}) (\S+) "([^"]*)" "([^"]*)" (\S+)$`)
+
+// Nginx parses Nginx's combined log format with a trailing request-time
+// field (seconds, fractional).
+type Nginx struct{}
+
+// NewNginx builds an Nginx combined-log-format parser.
+func NewNginx() *Nginx {
+	return &Nginx{}
+}
+
+// Parse matches raw against Nginx's combined-plus-request-time format.
+func (n *Nginx) Parse(ctx context.Context, raw string) (*log.LogEntry, error) {
+	m := nginxCombined.FindStringSubmatch(raw)
+	if m == nil {
+		return nil, fmt.Errorf("parse: nginx: line does not match combined log format")
+	}
+
+	ts, err := time.Parse(clfTimestamp, m[3])
+	if err != nil {
+		return nil, fmt.Errorf("parse: nginx: timestamp: %w", err)
+	}
+	status, _ := strconv.Atoi(m[6])
+
+	requestSeconds, _ := strconv.ParseFloat(m[10], 64)
+
+	entry := &log.LogEntry{
+		Timestamp:    ts,
+		Message:      raw,
+		ClientIP:     m[1],
+		Method:       m[4],
+		Path:         m[5],
+		StatusCode:   status,
+		ResponseTime: time.Duration(requestSeconds * float64(time.Second)),
+		UserAgent:    m[9],
+		Headers: map[string]string{
+			"Referer": m[8],
+		},
+		Metadata: map[string]interface{}{
+			"auth_user":  m[2],
+			"bytes_sent": parseSizeField(m[7]),
+			"access_log": "nginx",
+		},
+	}
+	return entry, nil
+}
+
+// Enrich applies the shared enrichment common to every format.
+func (n *Nginx) Enrich(ctx context.Context, entry *log.LogEntry) error {
+	return enrichCommon(ctx, entry)
+}
@@ -0,0 +1,77 @@
+// Package parse implements log.Parser for the structured formats the
+// gateway and its upstreams emit: JSON access logs, Apache/Nginx combined
+// log format, and GELF. Each format has its own Parser; Auto wraps a set of
+// them and dispatches each raw line to whichever one recognizes it.
+package parse
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/awesomeapibrasil/gateway-worker/internal/log"
+)
+
+// Auto dispatches Parse to the first of its Parsers that recognizes a raw
+// line, so a single pipeline can ingest mixed-format input without the
+// caller knowing the source format up front. Enrich is delegated to
+// whichever Parser originally parsed the entry, recorded in
+// entry.Metadata["parser"].
+type Auto struct {
+	parsers map[string]log.Parser
+	order   []string
+}
+
+// NewAuto builds an Auto parser trying formats in the given order. named
+// maps a format name (stored in entry.Metadata["parser"]) to its Parser;
+// order controls which is tried first.
+func NewAuto(named map[string]log.Parser, order []string) *Auto {
+	return &Auto{parsers: named, order: order}
+}
+
+// NewDefaultAuto builds an Auto parser covering every format this package
+// implements, trying the most specific (least ambiguous) formats first.
+func NewDefaultAuto() *Auto {
+	return NewAuto(map[string]log.Parser{
+		"gelf":   NewGELF(),
+		"json":   NewJSON(),
+		"apache": NewApache(),
+		"nginx":  NewNginx(),
+	}, []string{"gelf", "json", "apache", "nginx"})
+}
+
+// Parse tries each Parser in order, returning the first successful result.
+func (a *Auto) Parse(ctx context.Context, raw string) (*log.LogEntry, error) {
+	var lastErr error
+	for _, name := range a.order {
+		p, ok := a.parsers[name]
+		if !ok {
+			continue
+		}
+		entry, err := p.Parse(ctx, raw)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if entry.Metadata == nil {
+			entry.Metadata = make(map[string]interface{})
+		}
+		entry.Metadata["parser"] = name
+		return entry, nil
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("parse: no parser recognized the input")
+	}
+	return nil, fmt.Errorf("parse: auto: %w", lastErr)
+}
+
+// Enrich delegates to the Parser recorded in entry.Metadata["parser"], or
+// is a no-op if the entry wasn't produced by this Auto (or has no such
+// entry, e.g. it came from elsewhere in the pipeline already enriched).
+func (a *Auto) Enrich(ctx context.Context, entry *log.LogEntry) error {
+	name, _ := entry.Metadata["parser"].(string)
+	p, ok := a.parsers[name]
+	if !ok {
+		return nil
+	}
+	return p.Enrich(ctx, entry)
+}
@@ -4,15 +4,35 @@ package log
 
 import (
 	"context"
+	"fmt"
+	"sync"
 	"time"
 )
 
+const (
+	// processBatchSize is how many parsed entries ProcessLogs accumulates
+	// before archiving them as a batch.
+	processBatchSize = 100
+	// flushInterval bounds how long a partial batch can sit unarchived
+	// when entries arrive slower than processBatchSize.
+	flushInterval = 5 * time.Second
+	// recentWindow is how long ProcessLogs retains processed entries in
+	// memory for AnalyzeTraffic/DetectThreats to query without needing
+	// their own collection path.
+	recentWindow = 30 * time.Minute
+	// defaultThreatWindow is how far back DetectThreats looks by default.
+	defaultThreatWindow = 15 * time.Minute
+)
+
 // Processor handles log aggregation and processing
 type Processor struct {
 	aggregator Aggregator
 	parser     Parser
 	archiver   Archiver
 	analyzer   Analyzer
+
+	mu     sync.Mutex
+	recent []LogEntry
 }
 
 // Aggregator interface for log aggregation from Gateway instances
@@ -41,35 +61,35 @@ type Analyzer interface {
 
 // LogEntry represents a structured log entry
 type LogEntry struct {
-	Timestamp   time.Time         `json:"timestamp"`
-	Level       string            `json:"level"`
-	Source      string            `json:"source"`
-	Message     string            `json:"message"`
-	RequestID   string            `json:"request_id"`
-	Method      string            `json:"method"`
-	Path        string            `json:"path"`
-	StatusCode  int               `json:"status_code"`
-	ResponseTime time.Duration    `json:"response_time"`
-	ClientIP    string            `json:"client_ip"`
-	UserAgent   string            `json:"user_agent"`
-	Headers     map[string]string `json:"headers"`
-	Metadata    map[string]interface{} `json:"metadata"`
+	Timestamp    time.Time              `json:"timestamp"`
+	Level        string                 `json:"level"`
+	Source       string                 `json:"source"`
+	Message      string                 `json:"message"`
+	RequestID    string                 `json:"request_id"`
+	Method       string                 `json:"method"`
+	Path         string                 `json:"path"`
+	StatusCode   int                    `json:"status_code"`
+	ResponseTime time.Duration          `json:"response_time"`
+	ClientIP     string                 `json:"client_ip"`
+	UserAgent    string                 `json:"user_agent"`
+	Headers      map[string]string      `json:"headers"`
+	Metadata     map[string]interface{} `json:"metadata"`
 }
 
 // TrafficAnalysis represents traffic pattern analysis results
 type TrafficAnalysis struct {
-	Period       time.Duration `json:"period"`
-	TotalRequests int          `json:"total_requests"`
-	UniqueIPs    int          `json:"unique_ips"`
-	TopPaths     []PathStat   `json:"top_paths"`
-	StatusCodes  []StatusStat `json:"status_codes"`
+	Period        time.Duration     `json:"period"`
+	TotalRequests int               `json:"total_requests"`
+	UniqueIPs     int               `json:"unique_ips"`
+	TopPaths      []PathStat        `json:"top_paths"`
+	StatusCodes   []StatusStat      `json:"status_codes"`
 	ResponseTimes ResponseTimeStats `json:"response_times"`
 }
 
 // PathStat represents statistics for a specific path
 type PathStat struct {
-	Path   string `json:"path"`
-	Count  int    `json:"count"`
+	Path    string        `json:"path"`
+	Count   int           `json:"count"`
 	AvgTime time.Duration `json:"avg_time"`
 }
 
@@ -89,11 +109,11 @@ type ResponseTimeStats struct {
 
 // ThreatAlert represents a detected security threat
 type ThreatAlert struct {
-	Type        string    `json:"type"`
-	Severity    string    `json:"severity"`
-	Description string    `json:"description"`
-	Source      string    `json:"source"`
-	Timestamp   time.Time `json:"timestamp"`
+	Type        string                 `json:"type"`
+	Severity    string                 `json:"severity"`
+	Description string                 `json:"description"`
+	Source      string                 `json:"source"`
+	Timestamp   time.Time              `json:"timestamp"`
 	Details     map[string]interface{} `json:"details"`
 }
 
@@ -107,24 +127,124 @@ func New(aggregator Aggregator, parser Parser, archiver Archiver, analyzer Analy
 	}
 }
 
-// ProcessLogs processes logs from Gateway instances
+// ProcessLogs streams logs from source, parsing and enriching each entry,
+// archiving them in batches, and buffering recent entries in memory so
+// AnalyzeTraffic/DetectThreats can query them without a separate
+// collection pass. It runs until ctx is canceled or the stream closes.
 func (p *Processor) ProcessLogs(ctx context.Context, source string) error {
-	// TODO: Implement log processing pipeline
-	// 1. Collect logs from Gateway instances
-	// 2. Parse and enrich log entries
-	// 3. Analyze for patterns and threats
-	// 4. Archive processed logs
-	return nil
+	entries, err := p.aggregator.Stream(ctx, source)
+	if err != nil {
+		return fmt.Errorf("log processor: stream %s: %w", source, err)
+	}
+
+	batch := make([]LogEntry, 0, processBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := p.archiver.Archive(ctx, batch); err != nil {
+			return fmt.Errorf("log processor: archive: %w", err)
+		}
+		p.remember(batch)
+		batch = make([]LogEntry, 0, processBatchSize)
+		return nil
+	}
+
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			_ = flush()
+			return ctx.Err()
+
+		case raw, ok := <-entries:
+			if !ok {
+				return flush()
+			}
+			entry, err := p.parseAndEnrich(ctx, raw)
+			if err != nil {
+				continue
+			}
+			batch = append(batch, *entry)
+			if len(batch) >= processBatchSize {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+
+		case <-ticker.C:
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
 }
 
-// AnalyzeTraffic analyzes traffic patterns from logs
+// parseAndEnrich upgrades raw into a fully structured entry when it isn't
+// one already (Aggregator.Stream may hand back entries whose only
+// populated field is Message, carrying a still-raw log line from the
+// Gateway), then enriches it.
+func (p *Processor) parseAndEnrich(ctx context.Context, raw LogEntry) (*LogEntry, error) {
+	entry := raw
+	if entry.Method == "" && entry.Path == "" && entry.Message != "" {
+		if parsed, err := p.parser.Parse(ctx, entry.Message); err == nil {
+			if parsed.Source == "" {
+				parsed.Source = raw.Source
+			}
+			entry = *parsed
+		}
+	}
+
+	if err := p.parser.Enrich(ctx, &entry); err != nil {
+		return nil, fmt.Errorf("log processor: enrich: %w", err)
+	}
+	return &entry, nil
+}
+
+// remember appends batch to the in-memory recent buffer, trimming entries
+// older than recentWindow.
+func (p *Processor) remember(batch []LogEntry) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.recent = append(p.recent, batch...)
+
+	cutoff := time.Now().Add(-recentWindow)
+	trimmed := p.recent[:0]
+	for _, e := range p.recent {
+		if e.Timestamp.After(cutoff) {
+			trimmed = append(trimmed, e)
+		}
+	}
+	p.recent = trimmed
+}
+
+// since returns the buffered entries newer than cutoff.
+func (p *Processor) since(cutoff time.Time) []LogEntry {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	entries := make([]LogEntry, 0, len(p.recent))
+	for _, e := range p.recent {
+		if e.Timestamp.After(cutoff) {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// AnalyzeTraffic analyzes traffic patterns from logs processed by
+// ProcessLogs over the last period.
 func (p *Processor) AnalyzeTraffic(ctx context.Context, period time.Duration) (*TrafficAnalysis, error) {
-	// TODO: Implement traffic analysis
-	return nil, nil
+	entries := p.since(time.Now().Add(-period))
+	return p.analyzer.AnalyzeTraffic(ctx, entries)
 }
 
-// DetectThreats analyzes logs for security threats
+// DetectThreats analyzes logs processed by ProcessLogs over the last
+// defaultThreatWindow for security threats.
 func (p *Processor) DetectThreats(ctx context.Context) ([]ThreatAlert, error) {
-	// TODO: Implement threat detection
-	return nil, nil
-}
\ No newline at end of file
+	entries := p.since(time.Now().Add(-defaultThreatWindow))
+	return p.analyzer.DetectThreats(ctx, entries)
+}
@@ -0,0 +1,107 @@
+package sigma
+
+import (
+	"fmt"
+	"strings"
+)
+
+// evaluateCondition parses and evaluates a Detection.Condition expression
+// against the already-computed per-selection results. Grammar (lowest to
+// highest precedence): expr := term ("or" term)*; term := factor ("and"
+// factor)*; factor := "not" factor | selectionName | "(" expr ")".
+func evaluateCondition(condition string, results map[string]bool) (bool, error) {
+	tokens := tokenizeCondition(condition)
+	if len(tokens) == 0 {
+		return false, fmt.Errorf("sigma: empty condition")
+	}
+
+	p := &conditionParser{tokens: tokens, results: results}
+	value, err := p.parseExpr()
+	if err != nil {
+		return false, err
+	}
+	if p.pos != len(p.tokens) {
+		return false, fmt.Errorf("sigma: unexpected token %q in condition %q", p.tokens[p.pos], condition)
+	}
+	return value, nil
+}
+
+type conditionParser struct {
+	tokens  []string
+	pos     int
+	results map[string]bool
+}
+
+func (p *conditionParser) parseExpr() (bool, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return false, err
+	}
+	for p.peek() == "or" {
+		p.pos++
+		right, err := p.parseTerm()
+		if err != nil {
+			return false, err
+		}
+		left = left || right
+	}
+	return left, nil
+}
+
+func (p *conditionParser) parseTerm() (bool, error) {
+	left, err := p.parseFactor()
+	if err != nil {
+		return false, err
+	}
+	for p.peek() == "and" {
+		p.pos++
+		right, err := p.parseFactor()
+		if err != nil {
+			return false, err
+		}
+		left = left && right
+	}
+	return left, nil
+}
+
+func (p *conditionParser) parseFactor() (bool, error) {
+	switch p.peek() {
+	case "":
+		return false, fmt.Errorf("sigma: unexpected end of condition")
+	case "not":
+		p.pos++
+		value, err := p.parseFactor()
+		return !value, err
+	case "(":
+		p.pos++
+		value, err := p.parseExpr()
+		if err != nil {
+			return false, err
+		}
+		if p.peek() != ")" {
+			return false, fmt.Errorf("sigma: missing closing parenthesis")
+		}
+		p.pos++
+		return value, nil
+	default:
+		name := p.tokens[p.pos]
+		p.pos++
+		value, ok := p.results[name]
+		if !ok {
+			return false, fmt.Errorf("sigma: condition references unknown selection %q", name)
+		}
+		return value, nil
+	}
+}
+
+func (p *conditionParser) peek() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+func tokenizeCondition(condition string) []string {
+	spaced := strings.NewReplacer("(", " ( ", ")", " ) ").Replace(condition)
+	return strings.Fields(spaced)
+}
@@ -0,0 +1,93 @@
+package sigma
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/awesomeapibrasil/gateway-worker/internal/log"
+)
+
+// matches reports whether entry satisfies a single FieldMatch.
+func (fm FieldMatch) matches(entry log.LogEntry) bool {
+	value, ok := fieldValue(entry, fm.Field)
+	if !ok {
+		return false
+	}
+
+	switch fm.Type {
+	case MatchContains:
+		return strings.Contains(strings.ToLower(value), strings.ToLower(fm.Pattern))
+	case MatchRegex:
+		re, err := compileCached(fm.Pattern)
+		if err != nil {
+			return false
+		}
+		return re.MatchString(value)
+	case MatchEquals, "":
+		return strings.EqualFold(value, fm.Pattern)
+	default:
+		return false
+	}
+}
+
+// fieldValue extracts field from entry. Supported fields: message, path,
+// method, client_ip, user_agent, status_code, request_id, source, level,
+// and "header.<name>" / "metadata.<key>" for the map-valued fields.
+func fieldValue(entry log.LogEntry, field string) (string, bool) {
+	switch {
+	case field == "message":
+		return entry.Message, true
+	case field == "path":
+		return entry.Path, true
+	case field == "method":
+		return entry.Method, true
+	case field == "client_ip":
+		return entry.ClientIP, true
+	case field == "user_agent":
+		return entry.UserAgent, true
+	case field == "status_code":
+		return strconv.Itoa(entry.StatusCode), true
+	case field == "request_id":
+		return entry.RequestID, true
+	case field == "source":
+		return entry.Source, true
+	case field == "level":
+		return entry.Level, true
+	case strings.HasPrefix(field, "header."):
+		v, ok := entry.Headers[strings.TrimPrefix(field, "header.")]
+		return v, ok
+	case strings.HasPrefix(field, "metadata."):
+		v, ok := entry.Metadata[strings.TrimPrefix(field, "metadata.")]
+		if !ok {
+			return "", false
+		}
+		return fmt.Sprint(v), true
+	default:
+		return "", false
+	}
+}
+
+var (
+	regexCacheMu sync.Mutex
+	regexCache   = make(map[string]*regexp.Regexp)
+)
+
+// compileCached compiles pattern once per process; rules are static and
+// evaluated per log entry, so recompiling on every call would be wasteful.
+func compileCached(pattern string) (*regexp.Regexp, error) {
+	regexCacheMu.Lock()
+	defer regexCacheMu.Unlock()
+
+	if re, ok := regexCache[pattern]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	regexCache[pattern] = re
+	return re, nil
+}
@@ -0,0 +1,72 @@
+// Package sigma implements a Sigma-style detection rule engine: declarative
+// field-match selections combined by a small boolean condition expression,
+// evaluated against a single log entry at a time. It covers the common
+// subset of Sigma (https://github.com/SigmaHQ/sigma) that matters for
+// per-request detections; rules that aggregate across a time window are
+// out of scope here and belong in a separate rate-based check.
+package sigma
+
+import "github.com/awesomeapibrasil/gateway-worker/internal/log"
+
+// MatchType names how a Selection field's pattern is compared against the
+// entry.
+type MatchType string
+
+const (
+	// MatchEquals requires an exact, case-insensitive match.
+	MatchEquals MatchType = "equals"
+	// MatchContains requires the field to contain pattern as a substring,
+	// case-insensitive.
+	MatchContains MatchType = "contains"
+	// MatchRegex requires the field to match pattern as a regular
+	// expression.
+	MatchRegex MatchType = "regex"
+)
+
+// FieldMatch is one field-level test within a Selection.
+type FieldMatch struct {
+	Field   string
+	Type    MatchType
+	Pattern string
+}
+
+// Selection is a named group of FieldMatches that must ALL match (Sigma's
+// implicit AND within a selection) for the selection itself to be true.
+type Selection []FieldMatch
+
+// Detection is a rule's "detection" block: named selections plus a
+// Condition expression combining them. Condition supports selection names,
+// "and", "or", "not", and parentheses - e.g. "selection1 and not selection2".
+type Detection struct {
+	Selections map[string]Selection
+	Condition  string
+}
+
+// Rule is one Sigma-style detection rule.
+type Rule struct {
+	ID          string
+	Title       string
+	Description string
+	// Level is the rule's severity, matching log.ThreatAlert.Severity
+	// (e.g. "low", "medium", "high", "critical").
+	Level     string
+	Detection Detection
+}
+
+// Matches reports whether entry satisfies the rule's detection condition.
+func (r Rule) Matches(entry log.LogEntry) (bool, error) {
+	results := make(map[string]bool, len(r.Detection.Selections))
+	for name, sel := range r.Detection.Selections {
+		results[name] = sel.matches(entry)
+	}
+	return evaluateCondition(r.Detection.Condition, results)
+}
+
+func (s Selection) matches(entry log.LogEntry) bool {
+	for _, fm := range s {
+		if !fm.matches(entry) {
+			return false
+		}
+	}
+	return len(s) > 0
+}
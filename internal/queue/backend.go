@@ -0,0 +1,79 @@
+package queue
+
+import (
+	"context"
+	"time"
+)
+
+// Backend is the pluggable persistence and claiming layer behind Service.
+// kvBackend (the default, see kvbackend.go) stores jobs through the shared
+// storage.KV abstraction and is meant for single-instance deployments and
+// tests; RedisBackend (redis.go, build-tag gated) stores them in Redis data
+// structures purpose-built for queueing, for deployments that run several
+// worker replicas against one broker.
+type Backend interface {
+	// Enqueue persists job so it becomes claimable by Dequeue once
+	// job.VisibleAt has passed (a zero VisibleAt means immediately).
+	Enqueue(ctx context.Context, job Job) error
+
+	// Dequeue claims the next due job for workerID, marking it in-progress.
+	// It reports ok=false (not an error) if nothing is currently claimable.
+	Dequeue(ctx context.Context, workerID string) (job *Job, ok bool, err error)
+
+	// Ack marks job completed.
+	Ack(ctx context.Context, job Job) error
+
+	// Nack returns a failed job to the backend, claimable again at
+	// job.VisibleAt (or moved to the dead-letter status), per job.Status as
+	// set by the caller.
+	Nack(ctx context.Context, job Job) error
+
+	// Get returns the current persisted state of job id.
+	Get(ctx context.Context, id string) (*Job, error)
+
+	// Schedule registers (or replaces) the recurring job definition stored
+	// under name, due according to the 5-field cron expression spec.
+	Schedule(ctx context.Context, name, spec string, job Job) error
+
+	// DuePeriodic scans registered periodic definitions and returns the
+	// jobs whose occurrence at now is due to run, each claimed atomically
+	// so that overlapping scheduler leader transitions (two workers both
+	// briefly believing they're the leader) can't enqueue the same
+	// occurrence twice.
+	DuePeriodic(ctx context.Context, now time.Time) ([]Job, error)
+
+	// DeadLetters returns jobs currently in the dead-letter status,
+	// optionally narrowed by filter.
+	DeadLetters(ctx context.Context, filter DeadLetterFilter) ([]Job, error)
+}
+
+// DeadLetterFilter narrows Backend.DeadLetters / Service.DeadLetters. A zero
+// value (every field empty) returns every dead-lettered job.
+type DeadLetterFilter struct {
+	Type JobType
+}
+
+func (f DeadLetterFilter) matches(job Job) bool {
+	return f.Type == "" || f.Type == job.Type
+}
+
+// electable is implemented by backends shared across multiple worker
+// replicas, which therefore need a single elected scheduler to scan
+// DuePeriodic - otherwise every replica would scan on every tick. kvBackend
+// doesn't implement it: it's meant for single-instance use, so there's
+// nobody to elect against.
+type electable interface {
+	// electLeader attempts to become (or renew being) the scheduler leader.
+	// release must be called once this tick's work is done, regardless of
+	// whether leader is true.
+	electLeader(ctx context.Context) (leader bool, release func(), err error)
+}
+
+// reclaimer is implemented by backends that track in-progress jobs
+// separately from the ready set (RedisBackend) and therefore need a janitor
+// to requeue jobs whose visibility timeout lapsed because their worker
+// died. kvBackend doesn't need this: its claim scan already treats a
+// stale in-progress job as claimable again, see kvbackend.go.
+type reclaimer interface {
+	reclaimExpired(ctx context.Context) error
+}
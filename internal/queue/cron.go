@@ -0,0 +1,142 @@
+package queue
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronSchedule is a parsed standard 5-field cron expression (minute hour
+// day-of-month month day-of-week), evaluated minute by minute by the
+// periodic scheduler rather than computing a single "next run" time.
+type cronSchedule struct {
+	minutes  map[int]bool
+	hours    map[int]bool
+	doms     map[int]bool
+	months   map[int]bool
+	dows     map[int]bool
+	anyDom   bool
+	anyDow   bool
+}
+
+// parseCronSpec parses a standard 5-field cron expression: minute(0-59)
+// hour(0-23) day-of-month(1-31) month(1-12) day-of-week(0-6, Sunday=0).
+// Each field accepts "*", a single value, a comma-separated list, a range
+// ("a-b"), or a step ("*/n" or "a-b/n").
+func parseCronSpec(spec string) (*cronSchedule, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("queue: cron spec %q must have 5 fields, got %d", spec, len(fields))
+	}
+
+	minutes, err := parseCronField(fields[0], 0, 59)
+	if err != nil {
+		return nil, fmt.Errorf("queue: cron minute field: %w", err)
+	}
+	hours, err := parseCronField(fields[1], 0, 23)
+	if err != nil {
+		return nil, fmt.Errorf("queue: cron hour field: %w", err)
+	}
+	doms, err := parseCronField(fields[2], 1, 31)
+	if err != nil {
+		return nil, fmt.Errorf("queue: cron day-of-month field: %w", err)
+	}
+	months, err := parseCronField(fields[3], 1, 12)
+	if err != nil {
+		return nil, fmt.Errorf("queue: cron month field: %w", err)
+	}
+	dows, err := parseCronField(fields[4], 0, 6)
+	if err != nil {
+		return nil, fmt.Errorf("queue: cron day-of-week field: %w", err)
+	}
+
+	return &cronSchedule{
+		minutes: minutes,
+		hours:   hours,
+		doms:    doms,
+		months:  months,
+		dows:    dows,
+		anyDom:  fields[2] == "*",
+		anyDow:  fields[4] == "*",
+	}, nil
+}
+
+// matches reports whether t falls within this schedule, to minute
+// resolution. When both day-of-month and day-of-week are restricted (not
+// "*"), standard cron semantics treat them as an OR rather than an AND.
+func (c *cronSchedule) matches(t time.Time) bool {
+	if !c.minutes[t.Minute()] || !c.hours[t.Hour()] || !c.months[int(t.Month())] {
+		return false
+	}
+
+	domMatch := c.doms[t.Day()]
+	dowMatch := c.dows[int(t.Weekday())]
+	switch {
+	case c.anyDom && c.anyDow:
+		return true
+	case c.anyDom:
+		return dowMatch
+	case c.anyDow:
+		return domMatch
+	default:
+		return domMatch || dowMatch
+	}
+}
+
+func parseCronField(field string, min, max int) (map[int]bool, error) {
+	values := make(map[int]bool)
+
+	for _, part := range strings.Split(field, ",") {
+		rangeStr, step, err := splitCronStep(part)
+		if err != nil {
+			return nil, err
+		}
+
+		lo, hi := min, max
+		if rangeStr != "*" {
+			lo, hi, err = parseCronRange(rangeStr, min, max)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			if v < min || v > max {
+				return nil, fmt.Errorf("value %d out of range [%d,%d]", v, min, max)
+			}
+			values[v] = true
+		}
+	}
+
+	return values, nil
+}
+
+func splitCronStep(part string) (rangeStr string, step int, err error) {
+	pieces := strings.SplitN(part, "/", 2)
+	if len(pieces) == 1 {
+		return pieces[0], 1, nil
+	}
+	step, err = strconv.Atoi(pieces[1])
+	if err != nil || step <= 0 {
+		return "", 0, fmt.Errorf("invalid step %q", pieces[1])
+	}
+	return pieces[0], step, nil
+}
+
+func parseCronRange(rangeStr string, min, max int) (lo, hi int, err error) {
+	bounds := strings.SplitN(rangeStr, "-", 2)
+	lo, err = strconv.Atoi(bounds[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid value %q", bounds[0])
+	}
+	if len(bounds) == 1 {
+		return lo, lo, nil
+	}
+	hi, err = strconv.Atoi(bounds[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid value %q", bounds[1])
+	}
+	_ = max
+	return lo, hi, nil
+}
@@ -5,4 +5,11 @@ import "errors"
 var (
 	// ErrQueueFull is returned when the job queue is full
 	ErrQueueFull = errors.New("job queue is full")
-)
\ No newline at end of file
+
+	// ErrDuplicateJob is returned by Submit when a job carries an
+	// IdempotencyKey that has already been claimed for its JobType.
+	ErrDuplicateJob = errors.New("job queue: duplicate idempotency key")
+
+	// ErrJobNotFound is returned by GetJobStatus for an unknown job ID.
+	ErrJobNotFound = errors.New("job queue: job not found")
+)
@@ -0,0 +1,98 @@
+package queue
+
+import "time"
+
+// JobType represents different types of jobs the worker can process
+type JobType string
+
+const (
+	JobTypeCertificateRenewal    JobType = "certificate_renewal"
+	JobTypeCertificateValidation JobType = "certificate_validation"
+	JobTypeConfigUpdate          JobType = "config_update"
+	JobTypeLogProcessing         JobType = "log_processing"
+	JobTypeAnalytics             JobType = "analytics"
+	JobTypeDatabaseCleanup       JobType = "database_cleanup"
+	JobTypeIntegration           JobType = "integration"
+	JobTypeThreatFeedPoll        JobType = "threat_feed_poll"
+)
+
+// PriorityClass groups job types into broad scheduling tiers so a flood of
+// low-priority work (e.g. telemetry) can't starve emergency deploys.
+// Workers always drain PriorityEmergency before PriorityNormal, and
+// PriorityNormal before PriorityLow.
+type PriorityClass int
+
+const (
+	PriorityLow PriorityClass = iota
+	PriorityNormal
+	PriorityEmergency
+)
+
+// defaultPriority returns the priority class a job type falls into absent
+// an explicit Job.Priority override (emergency WAF deploys > routine
+// renewals > telemetry).
+func defaultPriority(t JobType) PriorityClass {
+	switch t {
+	case JobTypeConfigUpdate:
+		return PriorityEmergency
+	case JobTypeCertificateRenewal, JobTypeCertificateValidation, JobTypeDatabaseCleanup, JobTypeThreatFeedPoll:
+		return PriorityNormal
+	default:
+		return PriorityLow
+	}
+}
+
+// JobStatus tracks a job's lifecycle in the persistent store.
+type JobStatus string
+
+const (
+	JobStatusPending    JobStatus = "pending"
+	JobStatusInProgress JobStatus = "in_progress"
+	JobStatusCompleted  JobStatus = "completed"
+	JobStatusFailed     JobStatus = "failed"
+	JobStatusDeadLetter JobStatus = "dead_letter"
+)
+
+// Job represents a job to be processed
+type Job struct {
+	ID       string                 `json:"id"`
+	Type     JobType                `json:"type"`
+	Payload  map[string]interface{} `json:"payload"`
+	Priority int                    `json:"priority"`
+	Retry    int                    `json:"retry"`
+	MaxRetry int                    `json:"max_retry"`
+	Created  time.Time              `json:"created"`
+
+	// IdempotencyKey, when set, lets at-least-once redelivery avoid doing
+	// the same side effect twice: Submit refuses a second job carrying a
+	// key already seen for that JobType.
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+
+	Status    JobStatus `json:"status"`
+	VisibleAt time.Time `json:"visible_at"` // job is claimable once now >= VisibleAt
+	UpdatedAt time.Time `json:"updated_at"`
+	LastError string    `json:"last_error,omitempty"`
+	ClaimedBy string    `json:"claimed_by,omitempty"`
+
+	// Attempts records one entry per failed execution, most recent last, so
+	// a dead-lettered job's full failure history survives to DeadLetters.
+	Attempts []Attempt `json:"attempts,omitempty"`
+
+	// ReplayedFrom, when set, is the job ID this job was cloned from by
+	// Service.Replay, mirroring how upstream replay sources mark cloned
+	// requests with an OriginalID field so downstream consumers can tell
+	// replayed traffic from live traffic.
+	ReplayedFrom string `json:"replayed_from,omitempty"`
+}
+
+// Attempt records the outcome of one failed execution of a job.
+type Attempt struct {
+	At    time.Time `json:"at"`
+	Error string    `json:"error"`
+}
+
+// priorityClass returns the tier defaultPriority assigns to the job's type;
+// an explicit Job.Priority does not change it.
+func (j Job) priorityClass() PriorityClass {
+	return defaultPriority(j.Type)
+}
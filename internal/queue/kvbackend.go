@@ -0,0 +1,259 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/awesomeapibrasil/gateway-worker/internal/storage"
+)
+
+const (
+	jobKeyPrefix       = "queue/job/"
+	idempotencyPrefix  = "queue/idempotency/"
+	periodicPrefix     = "queue/periodic/policy/"
+	periodicOccPrefix  = "queue/periodic/occurrence/"
+	periodicScanPrefix = "queue/periodic/lastscan/"
+
+	// periodicLookback bounds how far into the past DuePeriodic catches up
+	// missed occurrences (e.g. after the process was down), so a policy
+	// that's been registered for months doesn't trigger a scan back to the
+	// epoch on its first tick.
+	periodicLookback = 24 * time.Hour
+)
+
+func jobKey(id string) string { return jobKeyPrefix + id }
+
+func idempotencyKey(jobType JobType, key string) string {
+	return idempotencyPrefix + string(jobType) + "/" + key
+}
+
+func periodicKey(name string) string { return periodicPrefix + name }
+
+func periodicOccurrenceKey(name string, occurrence time.Time) string {
+	return fmt.Sprintf("%s%s/%d", periodicOccPrefix, name, occurrence.Unix())
+}
+
+func periodicScanKey(name string) string { return periodicScanPrefix + name }
+
+// periodicPolicy is what Schedule persists for a named recurring job.
+type periodicPolicy struct {
+	Spec string `json:"spec"`
+	Job  Job    `json:"job"`
+}
+
+// kvBackend is the default Backend, persisting jobs through the shared KV
+// storage abstraction (internal/storage) rather than a dedicated queue
+// broker. It has no distributed scheduler election - every process that
+// calls DuePeriodic scans unconditionally - which is fine for the
+// single-instance/test deployments it's meant for.
+type kvBackend struct {
+	kv storage.KV
+}
+
+func newKVBackend(kv storage.KV) *kvBackend {
+	return &kvBackend{kv: kv}
+}
+
+func (b *kvBackend) Enqueue(ctx context.Context, job Job) error {
+	return b.save(ctx, job, true)
+}
+
+func (b *kvBackend) Ack(ctx context.Context, job Job) error {
+	return b.save(ctx, job, false)
+}
+
+func (b *kvBackend) Nack(ctx context.Context, job Job) error {
+	return b.save(ctx, job, false)
+}
+
+// save writes job's current state, and - for first-time submissions with an
+// idempotency key - claims that key so a duplicate Submit is rejected.
+func (b *kvBackend) save(ctx context.Context, job Job, claimIdempotency bool) error {
+	if claimIdempotency && job.IdempotencyKey != "" {
+		ok, err := b.kv.CompareAndSwap(ctx, idempotencyKey(job.Type, job.IdempotencyKey), nil, []byte(job.ID))
+		if err != nil {
+			return fmt.Errorf("claim idempotency key: %w", err)
+		}
+		if !ok {
+			return ErrDuplicateJob
+		}
+	}
+
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	return b.kv.Put(ctx, jobKey(job.ID), data, 0)
+}
+
+func (b *kvBackend) Get(ctx context.Context, id string) (*Job, error) {
+	data, err := b.kv.Get(ctx, jobKey(id))
+	if err != nil {
+		if err == storage.ErrNotFound {
+			return nil, ErrJobNotFound
+		}
+		return nil, err
+	}
+	var job Job
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// list returns every job currently persisted, regardless of status. Callers
+// filter by status/type as needed; the store itself has no index beyond "by
+// ID" because the worker's job volume doesn't warrant one.
+func (b *kvBackend) list(ctx context.Context) ([]*Job, error) {
+	keys, err := b.kv.List(ctx, jobKeyPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]*Job, 0, len(keys))
+	for _, key := range keys {
+		data, err := b.kv.Get(ctx, key)
+		if err != nil {
+			continue
+		}
+		var job Job
+		if err := json.Unmarshal(data, &job); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, &job)
+	}
+	return jobs, nil
+}
+
+// Dequeue finds the highest-priority claimable job (pending, or failed and
+// past its backoff delay, or in-progress but whose visibility timeout
+// lapsed) and marks it in-progress for workerID. A stale in-progress job -
+// left behind by a worker that died mid-job - is recovered this way without
+// a separate janitor, since every claim scans full job state anyway.
+func (b *kvBackend) Dequeue(ctx context.Context, workerID string) (*Job, bool, error) {
+	jobs, err := b.list(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	now := time.Now()
+	var candidates []*Job
+	for _, j := range jobs {
+		switch j.Status {
+		case JobStatusPending, JobStatusFailed:
+			if !j.VisibleAt.IsZero() && j.VisibleAt.After(now) {
+				continue
+			}
+		case JobStatusInProgress:
+			if j.VisibleAt.After(now) {
+				continue // still within its visibility timeout
+			}
+		default:
+			continue
+		}
+		candidates = append(candidates, j)
+	}
+	if len(candidates) == 0 {
+		return nil, false, nil
+	}
+
+	best := candidates[0]
+	for _, c := range candidates[1:] {
+		if c.Priority > best.Priority || (c.Priority == best.Priority && c.Created.Before(best.Created)) {
+			best = c
+		}
+	}
+
+	best.Status = JobStatusInProgress
+	best.ClaimedBy = workerID
+	best.VisibleAt = now.Add(visibilityTimeout)
+	best.UpdatedAt = now
+
+	if err := b.save(ctx, *best, false); err != nil {
+		return nil, false, fmt.Errorf("dequeue: persist claim for job %s: %w", best.ID, err)
+	}
+	return best, true, nil
+}
+
+// Schedule persists (or replaces) the recurring job definition for name.
+func (b *kvBackend) Schedule(ctx context.Context, name, spec string, job Job) error {
+	if _, err := parseCronSpec(spec); err != nil {
+		return err
+	}
+	data, err := json.Marshal(periodicPolicy{Spec: spec, Job: job})
+	if err != nil {
+		return err
+	}
+	return b.kv.Put(ctx, periodicKey(name), data, 0)
+}
+
+// DuePeriodic scans every registered policy for occurrences due at or
+// before now (catching up at most periodicLookback of missed minutes), and
+// claims each due occurrence via a CompareAndSwap on a per-minute marker so
+// two processes racing the same tick only enqueue it once.
+func (b *kvBackend) DuePeriodic(ctx context.Context, now time.Time) ([]Job, error) {
+	keys, err := b.kv.List(ctx, periodicPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var due []Job
+	for _, key := range keys {
+		data, err := b.kv.Get(ctx, key)
+		if err != nil {
+			continue
+		}
+		var policy periodicPolicy
+		if err := json.Unmarshal(data, &policy); err != nil {
+			continue
+		}
+		schedule, err := parseCronSpec(policy.Spec)
+		if err != nil {
+			continue
+		}
+		name := key[len(periodicPrefix):]
+
+		start := now.Add(-periodicLookback).Truncate(time.Minute)
+		if last, err := b.kv.Get(ctx, periodicScanKey(name)); err == nil {
+			if t, perr := time.Parse(time.RFC3339, string(last)); perr == nil && t.After(start) {
+				start = t
+			}
+		}
+
+		for minute := start.Add(time.Minute); !minute.After(now); minute = minute.Add(time.Minute) {
+			if !schedule.matches(minute) {
+				continue
+			}
+			ok, err := b.kv.CompareAndSwap(ctx, periodicOccurrenceKey(name, minute), nil, []byte("1"))
+			if err != nil || !ok {
+				continue
+			}
+			occurrence := policy.Job
+			occurrence.ID = ""
+			due = append(due, occurrence)
+		}
+
+		_ = b.kv.Put(ctx, periodicScanKey(name), []byte(now.Truncate(time.Minute).Format(time.RFC3339)), 0)
+	}
+
+	return due, nil
+}
+
+// DeadLetters scans every persisted job for ones in the dead-letter status,
+// since kvBackend keeps no separate index by status.
+func (b *kvBackend) DeadLetters(ctx context.Context, filter DeadLetterFilter) ([]Job, error) {
+	jobs, err := b.list(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []Job
+	for _, j := range jobs {
+		if j.Status == JobStatusDeadLetter && filter.matches(*j) {
+			out = append(out, *j)
+		}
+	}
+	return out, nil
+}
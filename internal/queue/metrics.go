@@ -0,0 +1,72 @@
+package queue
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// metrics holds the Prometheus collectors describing queue health. The caller
+// registers them through Collectors (main.go adds them to the health server's
+// /metrics/v3/queue/jobs group, see internal/health).
+type metrics struct {
+	depth       *prometheus.GaugeVec // by priority class
+	depthByType *prometheus.GaugeVec // by job type
+	inFlight    prometheus.Gauge
+	deadLetters prometheus.Gauge
+	processed   *prometheus.CounterVec   // by job type, result=success|failure
+	waitSeconds *prometheus.HistogramVec // by job type: Submit to claim latency
+	starvation  *prometheus.CounterVec   // by job type: claimed but declined by ConfigureType admission control
+}
+
+func newMetrics() *metrics {
+	return &metrics{
+		depth: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "gateway_worker",
+			Subsystem: "queue",
+			Name:      "depth",
+			Help:      "Number of jobs waiting to be claimed, by priority class.",
+		}, []string{"priority"}),
+		depthByType: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "gateway_worker",
+			Subsystem: "queue",
+			Name:      "depth_by_type",
+			Help:      "Number of jobs waiting to be claimed, by job type.",
+		}, []string{"type"}),
+		inFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "gateway_worker",
+			Subsystem: "queue",
+			Name:      "in_flight",
+			Help:      "Number of jobs currently claimed by a worker.",
+		}),
+		deadLetters: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: "gateway_worker",
+			Subsystem: "queue",
+			Name:      "dead_letters",
+			Help:      "Number of jobs that exhausted their retry budget.",
+		}),
+		processed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "gateway_worker",
+			Subsystem: "queue",
+			Name:      "processed_total",
+			Help:      "Total jobs processed, labeled by type and result.",
+		}, []string{"type", "result"}),
+		waitSeconds: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "gateway_worker",
+			Subsystem: "queue",
+			Name:      "wait_seconds",
+			Help:      "Time between Submit and a job being claimed by a worker, by job type.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"type"}),
+		starvation: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "gateway_worker",
+			Subsystem: "queue",
+			Name:      "starvation_total",
+			Help:      "Times a claimed job was declined by ConfigureType admission control and deferred to let other types through.",
+		}, []string{"type"}),
+	}
+}
+
+// Collectors returns every collector so the caller can register them with a
+// prometheus.Registerer (see health.MetricsRegistry).
+func (m *metrics) Collectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		m.depth, m.depthByType, m.inFlight, m.deadLetters, m.processed, m.waitSeconds, m.starvation,
+	}
+}
@@ -0,0 +1,364 @@
+//go:build !noredis
+
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	redisReadyKey       = "queue:ready"
+	redisInProgressKey  = "queue:inprogress"
+	redisDeadLetterKey  = "queue:deadletter"
+	redisIdempotencyKey = "queue:idempotency"
+	redisJobsKey        = "queue:jobs"
+	redisPeriodicKey    = "queue:periodic"
+	redisLeaderKey      = "queue:scheduler:leader"
+	redisOccurrenceKey  = "queue:periodic:occurrence"
+	redisScanPrefix     = "queue:periodic:lastscan:"
+	redisLeaderTTL      = 30 * time.Second
+	redisDequeuePoll    = 2 * time.Second
+	redisOccurrenceKeep = 48 * time.Hour
+
+	// redisPriorityWeight spaces priority bands far enough apart in the
+	// ready-set score that no realistic ready-at timestamp can push a
+	// lower-priority job ahead of a higher-priority one - it comfortably
+	// exceeds any UnixMilli value for the foreseeable lifetime of this
+	// queue. Mirrors kvBackend.Dequeue's Priority-then-Created ordering.
+	redisPriorityWeight = 1e13
+)
+
+// RedisBackend is a Backend implementation purpose-built for a queue broker
+// shared across several worker replicas, unlike kvBackend's general KV
+// abstraction. Ready jobs live in a sorted set keyed by ready-at timestamp
+// (redisReadyKey), so delayed and scheduled jobs coexist with immediate
+// ones; claimed jobs move into a second sorted set keyed by their
+// visibility deadline (redisInProgressKey) so a janitor can find and
+// requeue ones whose worker died. Job bodies live in a single hash
+// (redisJobsKey) keyed by job ID.
+type RedisBackend struct {
+	client *redis.Client
+	owner  string
+}
+
+// NewRedisBackend connects to the Redis instance described by dsn (a
+// redis:// URL, as accepted by redis.ParseURL).
+func NewRedisBackend(ctx context.Context, dsn string) (*RedisBackend, error) {
+	opts, err := redis.ParseURL(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("queue: parse redis dsn: %w", err)
+	}
+	client := redis.NewClient(opts)
+	if err := client.Ping(ctx).Err(); err != nil {
+		return nil, fmt.Errorf("queue: connect to redis: %w", err)
+	}
+	return &RedisBackend{client: client, owner: generateJobID()}, nil
+}
+
+// Close releases the underlying Redis connection.
+func (b *RedisBackend) Close() error {
+	return b.client.Close()
+}
+
+// readyScore computes job's position in the ready set: lower scores are
+// popped first by BZPOPMIN, so a job's Priority dominates the score
+// (higher priority -> lower score -> popped sooner) and its ready-at time
+// only breaks ties within the same priority band. Without this, the Redis
+// backend would be strict FIFO-by-time and a flood of low-priority jobs
+// could starve a high-priority one indefinitely.
+func readyScore(job Job) float64 {
+	readyAt := job.VisibleAt
+	if readyAt.IsZero() {
+		readyAt = time.Now()
+	}
+	return -float64(job.Priority)*redisPriorityWeight + float64(readyAt.UnixMilli())
+}
+
+// Enqueue persists job and makes it claimable. If job.IdempotencyKey is set,
+// it's claimed via HSETNX on redisIdempotencyKey first - mirroring
+// kvBackend.save's CompareAndSwap on the same logical key - so a duplicate
+// Submit for an already-claimed key returns ErrDuplicateJob instead of
+// silently enqueuing twice.
+func (b *RedisBackend) Enqueue(ctx context.Context, job Job) error {
+	if job.IdempotencyKey != "" {
+		claimed, err := b.client.HSetNX(ctx, redisIdempotencyKey, idempotencyKey(job.Type, job.IdempotencyKey), job.ID).Result()
+		if err != nil {
+			return fmt.Errorf("queue: claim idempotency key: %w", err)
+		}
+		if !claimed {
+			return ErrDuplicateJob
+		}
+	}
+
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	pipe := b.client.TxPipeline()
+	pipe.HSet(ctx, redisJobsKey, job.ID, data)
+	pipe.ZAdd(ctx, redisReadyKey, redis.Z{Score: readyScore(job), Member: job.ID})
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// Dequeue pops the lowest-scored (highest-priority, then earliest ready-at)
+// member of the ready set with BZPOPMIN, waiting up to redisDequeuePoll for
+// one to appear. If the popped job's ready-at is still in the future (a
+// delayed or scheduled job sorted ahead of anything currently due), it's
+// pushed back and Dequeue reports nothing claimable rather than
+// busy-looping - the next tick will pick it up once it's actually due.
+func (b *RedisBackend) Dequeue(ctx context.Context, workerID string) (*Job, bool, error) {
+	result, err := b.client.BZPopMin(ctx, redisDequeuePoll, redisReadyKey).Result()
+	if err == redis.Nil {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("queue: bzpopmin: %w", err)
+	}
+
+	id, _ := result.Member.(string)
+
+	data, err := b.client.HGet(ctx, redisJobsKey, id).Bytes()
+	if err == redis.Nil {
+		// Job body is gone (e.g. raced with a Nack that moved it
+		// elsewhere); nothing to claim this round.
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("queue: load job %s: %w", id, err)
+	}
+
+	var job Job
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, false, fmt.Errorf("queue: decode job %s: %w", id, err)
+	}
+
+	if job.VisibleAt.After(time.Now()) {
+		b.client.ZAdd(ctx, redisReadyKey, redis.Z{Score: result.Score, Member: id})
+		return nil, false, nil
+	}
+
+	now := time.Now()
+	job.Status = JobStatusInProgress
+	job.ClaimedBy = workerID
+	job.VisibleAt = now.Add(visibilityTimeout)
+	job.UpdatedAt = now
+
+	if err := b.persistInProgress(ctx, job); err != nil {
+		return nil, false, err
+	}
+	return &job, true, nil
+}
+
+func (b *RedisBackend) persistInProgress(ctx context.Context, job Job) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	pipe := b.client.TxPipeline()
+	pipe.HSet(ctx, redisJobsKey, job.ID, data)
+	pipe.ZAdd(ctx, redisInProgressKey, redis.Z{Score: float64(job.VisibleAt.UnixMilli()), Member: job.ID})
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// Ack removes job from the in-progress set and records its final state.
+func (b *RedisBackend) Ack(ctx context.Context, job Job) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	pipe := b.client.TxPipeline()
+	pipe.HSet(ctx, redisJobsKey, job.ID, data)
+	pipe.ZRem(ctx, redisInProgressKey, job.ID)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// Nack moves job out of the in-progress set and, depending on job.Status as
+// set by the caller, either back into the ready set at its (already
+// backoff-delayed) VisibleAt, or into the dead-letter set for DeadLetters
+// to find.
+func (b *RedisBackend) Nack(ctx context.Context, job Job) error {
+	data, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	pipe := b.client.TxPipeline()
+	pipe.HSet(ctx, redisJobsKey, job.ID, data)
+	pipe.ZRem(ctx, redisInProgressKey, job.ID)
+	if job.Status == JobStatusDeadLetter {
+		pipe.ZAdd(ctx, redisDeadLetterKey, redis.Z{Score: float64(job.UpdatedAt.UnixMilli()), Member: job.ID})
+	} else {
+		pipe.ZAdd(ctx, redisReadyKey, redis.Z{Score: readyScore(job), Member: job.ID})
+	}
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+func (b *RedisBackend) Get(ctx context.Context, id string) (*Job, error) {
+	data, err := b.client.HGet(ctx, redisJobsKey, id).Bytes()
+	if err == redis.Nil {
+		return nil, ErrJobNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var job Job
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// DeadLetters reads every job ID in the dead-letter set and loads its body,
+// skipping any that raced with another process deleting it in between.
+func (b *RedisBackend) DeadLetters(ctx context.Context, filter DeadLetterFilter) ([]Job, error) {
+	ids, err := b.client.ZRange(ctx, redisDeadLetterKey, 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	var out []Job
+	for _, id := range ids {
+		data, err := b.client.HGet(ctx, redisJobsKey, id).Bytes()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			return out, fmt.Errorf("queue: load dead-lettered job %s: %w", id, err)
+		}
+		var job Job
+		if err := json.Unmarshal(data, &job); err != nil {
+			return out, fmt.Errorf("queue: decode dead-lettered job %s: %w", id, err)
+		}
+		if filter.matches(job) {
+			out = append(out, job)
+		}
+	}
+	return out, nil
+}
+
+func (b *RedisBackend) Schedule(ctx context.Context, name, spec string, job Job) error {
+	if _, err := parseCronSpec(spec); err != nil {
+		return err
+	}
+	data, err := json.Marshal(periodicPolicy{Spec: spec, Job: job})
+	if err != nil {
+		return err
+	}
+	return b.client.HSet(ctx, redisPeriodicKey, name, data).Err()
+}
+
+// DuePeriodic mirrors kvBackend.DuePeriodic's catch-up and dedup logic, but
+// against Redis hashes: a per-occurrence field in redisOccurrenceKey set
+// with HSETNX is the atomic claim that keeps overlapping scheduler leaders
+// from double-enqueuing the same minute.
+func (b *RedisBackend) DuePeriodic(ctx context.Context, now time.Time) ([]Job, error) {
+	policies, err := b.client.HGetAll(ctx, redisPeriodicKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	var due []Job
+	for name, raw := range policies {
+		var policy periodicPolicy
+		if err := json.Unmarshal([]byte(raw), &policy); err != nil {
+			continue
+		}
+		schedule, err := parseCronSpec(policy.Spec)
+		if err != nil {
+			continue
+		}
+
+		start := now.Add(-periodicLookback).Truncate(time.Minute)
+		if last, err := b.client.Get(ctx, redisScanPrefix+name).Result(); err == nil {
+			if ms, perr := time.Parse(time.RFC3339, last); perr == nil && ms.After(start) {
+				start = ms
+			}
+		}
+
+		for minute := start.Add(time.Minute); !minute.After(now); minute = minute.Add(time.Minute) {
+			if !schedule.matches(minute) {
+				continue
+			}
+			occField := fmt.Sprintf("%s/%d", name, minute.Unix())
+			claimed, err := b.client.HSetNX(ctx, redisOccurrenceKey, occField, "1").Result()
+			if err != nil || !claimed {
+				continue
+			}
+			occurrence := policy.Job
+			occurrence.ID = ""
+			due = append(due, occurrence)
+		}
+
+		b.client.Set(ctx, redisScanPrefix+name, now.Truncate(time.Minute).Format(time.RFC3339), redisOccurrenceKeep)
+	}
+
+	return due, nil
+}
+
+// electLeaderScript extends the election TTL if owner already holds it, or
+// claims it if nobody (live) does, atomically so two processes can't both
+// believe they're the leader for even one tick.
+const electLeaderScript = `
+local current = redis.call("GET", KEYS[1])
+if current == false or current == ARGV[1] then
+	redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2])
+	return 1
+end
+return 0
+`
+
+// electLeader implements electable: it's what makes RunExclusive-style
+// single-scheduler behavior work across replicas sharing this backend.
+func (b *RedisBackend) electLeader(ctx context.Context) (bool, func(), error) {
+	result, err := b.client.Eval(ctx, electLeaderScript, []string{redisLeaderKey}, b.owner, redisLeaderTTL.Milliseconds()).Int()
+	if err != nil {
+		return false, func() {}, fmt.Errorf("queue: elect scheduler leader: %w", err)
+	}
+	return result == 1, func() {}, nil
+}
+
+// reclaimScript requeues an in-progress job only if it's still the
+// same job ID (it hasn't already been re-claimed), matching the
+// compare-and-swap discipline every other state transition in this backend
+// uses. It re-scores each job by its priority the same way readyScore does,
+// by reading the job's priority field back out of redisJobsKey, so a
+// reclaimed job doesn't lose its place in line to the plain ready-at score
+// readyScore guards against elsewhere.
+const reclaimScript = `
+local expired = redis.call("ZRANGEBYSCORE", KEYS[1], "-inf", ARGV[1])
+for _, id in ipairs(expired) do
+	redis.call("ZREM", KEYS[1], id)
+	local score = tonumber(ARGV[1])
+	local data = redis.call("HGET", KEYS[3], id)
+	if data then
+		local ok, job = pcall(cjson.decode, data)
+		if ok and job.priority then
+			score = score - (job.priority * tonumber(ARGV[2]))
+		end
+	end
+	redis.call("ZADD", KEYS[2], score, id)
+end
+return #expired
+`
+
+// reclaimExpired implements reclaimer: it moves every in-progress job whose
+// visibility deadline has passed back onto the ready set, so a worker that
+// died mid-job doesn't strand it forever.
+func (b *RedisBackend) reclaimExpired(ctx context.Context) error {
+	nowMs := time.Now().UnixMilli()
+	keys := []string{redisInProgressKey, redisReadyKey, redisJobsKey}
+	if err := b.client.Eval(ctx, reclaimScript, keys, nowMs, redisPriorityWeight).Err(); err != nil {
+		return fmt.Errorf("queue: reclaim expired jobs: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package queue
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// TypeOptions configures per-JobType admission control, set via
+// Service.ConfigureType. MaxConcurrency bounds how many jobs of that type
+// may be in flight at once; RatePerSecond throttles how often new ones may
+// start via a token bucket; Weight favors that type's jobs over other
+// currently-throttled types when deciding how soon a declined job gets
+// another turn (see requeueDelay). Zero values mean "unlimited" for
+// MaxConcurrency/RatePerSecond and "default" for Weight.
+type TypeOptions struct {
+	MaxConcurrency int
+	RatePerSecond  float64
+	Weight         int
+}
+
+// typeLimiter enforces one JobType's TypeOptions across every worker
+// sharing this Service.
+type typeLimiter struct {
+	mu         sync.Mutex
+	opts       TypeOptions
+	inFlight   int
+	tokens     float64
+	lastRefill time.Time
+}
+
+func newTypeLimiter(opts TypeOptions) *typeLimiter {
+	return &typeLimiter{opts: opts, tokens: opts.RatePerSecond, lastRefill: time.Now()}
+}
+
+// tryAdmit reports whether a job of this type may start now, reserving a
+// concurrency slot and a rate-limit token if so. Callers that get ok=true
+// must call release once the job finishes, success or failure, to free the
+// concurrency slot back up.
+func (l *typeLimiter) tryAdmit() (ok bool, release func()) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.opts.MaxConcurrency > 0 && l.inFlight >= l.opts.MaxConcurrency {
+		return false, nil
+	}
+
+	if l.opts.RatePerSecond > 0 {
+		now := time.Now()
+		l.tokens = math.Min(l.opts.RatePerSecond, l.tokens+now.Sub(l.lastRefill).Seconds()*l.opts.RatePerSecond)
+		l.lastRefill = now
+		if l.tokens < 1 {
+			return false, nil
+		}
+		l.tokens--
+	}
+
+	l.inFlight++
+	return true, func() {
+		l.mu.Lock()
+		l.inFlight--
+		l.mu.Unlock()
+	}
+}
+
+// requeueDelay controls how soon a job declined purely by admission control
+// (not a real handler failure) becomes claimable again. Backends claim one
+// job at a time rather than exposing every current candidate, so exact
+// weighted fair queueing across types isn't possible; scaling the delay
+// inversely with Weight approximates it - a higher-weight type's declined
+// jobs come back up for grabs sooner, winning more of the turns among
+// types that are otherwise equally eligible by priority.
+func requeueDelay(weight int) time.Duration {
+	if weight <= 0 {
+		weight = 1
+	}
+	return (200 * time.Millisecond) / time.Duration(weight)
+}
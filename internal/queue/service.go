@@ -2,146 +2,663 @@ package queue
 
 import (
 	"context"
+	cryptorand "crypto/rand"
+	"encoding/hex"
+	"fmt"
 	"log"
+	"math/rand"
 	"sync"
 	"time"
+
+	"github.com/awesomeapibrasil/gateway-worker/internal/storage"
+	"github.com/awesomeapibrasil/gateway-worker/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
-// JobType represents different types of jobs the worker can process
-type JobType string
+// visibilityTimeout bounds how long a claimed job stays invisible to other
+// workers before it's considered abandoned (worker crashed mid-job) and
+// becomes claimable again.
+const visibilityTimeout = 2 * time.Minute
 
 const (
-	JobTypeCertificateRenewal    JobType = "certificate_renewal"
-	JobTypeCertificateValidation JobType = "certificate_validation"
-	JobTypeConfigUpdate          JobType = "config_update"
-	JobTypeLogProcessing         JobType = "log_processing"
-	JobTypeAnalytics             JobType = "analytics"
-	JobTypeDatabaseCleanup       JobType = "database_cleanup"
-	JobTypeIntegration           JobType = "integration"
+	backoffBase = 5 * time.Second
+	backoffMax  = 15 * time.Minute
 )
 
-// Job represents a job to be processed
-type Job struct {
-	ID       string                 `json:"id"`
-	Type     JobType                `json:"type"`
-	Payload  map[string]interface{} `json:"payload"`
-	Priority int                    `json:"priority"`
-	Retry    int                    `json:"retry"`
-	MaxRetry int                    `json:"max_retry"`
-	Created  time.Time              `json:"created"`
+// schedulerInterval is how often Start's scheduler goroutine checks
+// registered periodic jobs for due occurrences.
+const schedulerInterval = 30 * time.Second
+
+// janitorInterval is how often Start's janitor goroutine asks the backend
+// to requeue in-progress jobs whose visibility timeout lapsed. Only
+// backends that track in-progress jobs outside the ready set need this
+// (see reclaimer in backend.go); kvBackend is a no-op here.
+const janitorInterval = 30 * time.Second
+
+// JobHandler processes one job type's jobs. RegisterHandler associates one
+// with a JobType so dispatch no longer needs a hardcoded switch case for
+// every job type Service knows about.
+type JobHandler interface {
+	Handle(ctx context.Context, job Job) error
+}
+
+// JobHandlerFunc adapts a plain function to JobHandler.
+type JobHandlerFunc func(ctx context.Context, job Job) error
+
+// Handle calls f.
+func (f JobHandlerFunc) Handle(ctx context.Context, job Job) error { return f(ctx, job) }
+
+// registeredHandler pairs a JobHandler with the name surfaced through
+// FailedJob.HandlerName and JobFailureEvent.HandlerName for operator triage.
+type registeredHandler struct {
+	name    string
+	handler JobHandler
+}
+
+// Notifier is the minimal alerting surface Service needs to raise a
+// structured event when a job is dead-lettered. It's defined locally
+// (rather than reusing internal/integration.Notifier) so this package has
+// no dependency on the integration feature layer; callers that want alerts
+// routed through internal/integration adapt Manager.SendAlert to it.
+type Notifier interface {
+	NotifyJobFailure(ctx context.Context, event JobFailureEvent) error
+}
+
+// JobFailureEvent describes a job that exhausted its retry budget, for
+// surfacing through whatever alerting channel the caller wires up via
+// SetNotifier.
+type JobFailureEvent struct {
+	JobID       string
+	JobType     JobType
+	HandlerName string
+	LastError   string
+	Attempts    []Attempt
 }
 
-// Service provides job queue functionality
+// FailedJob is the dead-letter view of a job returned by DeadLetters.
+type FailedJob struct {
+	Job
+	HandlerName string `json:"handler_name"`
+}
+
+// Service provides job queue functionality backed by a pluggable Backend,
+// so submitted jobs survive worker restarts with at-least-once delivery.
 type Service struct {
-	jobs    chan Job
-	workers int
-	wg      sync.WaitGroup
+	backend  Backend
+	wg       sync.WaitGroup
+	metrics  *metrics
+	handlers map[JobType]registeredHandler
+	notifier Notifier
+
+	limitersMu sync.RWMutex
+	limiters   map[JobType]*typeLimiter
+
+	// mu guards the live worker pool so SetWorkerCount can resize it - start
+	// or cancel individual worker goroutines - while Start's own goroutine is
+	// blocked in s.wg.Wait().
+	mu            sync.Mutex
+	workers       int
+	baseCtx       context.Context
+	workerCancels map[int]context.CancelFunc
+	nextWorkerID  int
+
+	notify chan struct{}
 }
 
-// New creates a new queue service
-func New() *Service {
-	return &Service{
-		jobs:    make(chan Job, 1000), // Buffer for 1000 jobs
-		workers: 5,                    // Default 5 workers
+// New creates a new queue service persisting jobs through kv, using the
+// default KV-backed Backend. Use NewWithBackend for a dedicated broker
+// backend such as Redis.
+func New(kv storage.KV) *Service {
+	return NewWithBackend(newKVBackend(kv))
+}
+
+// NewWithBackend creates a new queue service persisting jobs through the
+// given Backend. The job types defined in job.go are registered with their
+// (still placeholder) default handlers; call RegisterHandler to replace
+// one.
+func NewWithBackend(backend Backend) *Service {
+	s := &Service{
+		backend:       backend,
+		workers:       5, // Default 5 workers
+		metrics:       newMetrics(),
+		handlers:      make(map[JobType]registeredHandler),
+		limiters:      make(map[JobType]*typeLimiter),
+		workerCancels: make(map[int]context.CancelFunc),
+		notify:        make(chan struct{}, 1),
 	}
+	s.registerDefaultHandlers()
+	return s
 }
 
-// Start begins processing jobs
-func (s *Service) Start(ctx context.Context) error {
-	log.Printf("Starting %d queue workers", s.workers)
+func (s *Service) registerDefaultHandlers() {
+	s.RegisterHandler(JobTypeCertificateRenewal, "processCertificateRenewal", JobHandlerFunc(s.processCertificateRenewal))
+	s.RegisterHandler(JobTypeCertificateValidation, "processCertificateValidation", JobHandlerFunc(s.processCertificateValidation))
+	s.RegisterHandler(JobTypeConfigUpdate, "processConfigUpdate", JobHandlerFunc(s.processConfigUpdate))
+	s.RegisterHandler(JobTypeLogProcessing, "processLogProcessing", JobHandlerFunc(s.processLogProcessing))
+	s.RegisterHandler(JobTypeAnalytics, "processAnalytics", JobHandlerFunc(s.processAnalytics))
+	s.RegisterHandler(JobTypeDatabaseCleanup, "processDatabaseCleanup", JobHandlerFunc(s.processDatabaseCleanup))
+	s.RegisterHandler(JobTypeIntegration, "processIntegration", JobHandlerFunc(s.processIntegration))
+	s.RegisterHandler(JobTypeThreatFeedPoll, "processThreatFeedPoll", JobHandlerFunc(s.processThreatFeedPoll))
+}
 
-	for i := 0; i < s.workers; i++ {
+// RegisterHandler associates handler, reported under name in dead-letter
+// and failure-event output, with jobType. Registering the same jobType
+// twice replaces the previous handler - callers override a default handler
+// this way.
+func (s *Service) RegisterHandler(jobType JobType, name string, handler JobHandler) {
+	s.handlers[jobType] = registeredHandler{name: name, handler: handler}
+}
+
+// SetNotifier wires n to receive a JobFailureEvent whenever a job is
+// dead-lettered. Nil (the default) disables failure alerting.
+func (s *Service) SetNotifier(n Notifier) {
+	s.notifier = n
+}
+
+// ConfigureType sets admission-control options for jobType: MaxConcurrency
+// caps how many of its jobs may be in flight at once, RatePerSecond token-
+// buckets how often new ones may start, and Weight favors it when deciding
+// how soon a job declined by admission control gets another turn. Call any
+// time; workers pick up the new limits on their next claim. Passing a zero
+// TypeOptions removes any limit previously configured for jobType.
+func (s *Service) ConfigureType(t JobType, opts TypeOptions) {
+	s.limitersMu.Lock()
+	defer s.limitersMu.Unlock()
+	if opts == (TypeOptions{}) {
+		delete(s.limiters, t)
+		return
+	}
+	s.limiters[t] = newTypeLimiter(opts)
+}
+
+func (s *Service) limiterFor(t JobType) *typeLimiter {
+	s.limitersMu.RLock()
+	defer s.limitersMu.RUnlock()
+	return s.limiters[t]
+}
+
+// SetWorkerCount resizes the live worker pool to n, starting new worker
+// goroutines or canceling existing ones as needed. Safe to call before
+// Start (it just changes the initial count) or at any point afterward.
+func (s *Service) SetWorkerCount(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.workers = n
+	if s.baseCtx != nil {
+		s.resizeLocked(n)
+	}
+}
+
+// resizeLocked starts or cancels worker goroutines so exactly n are running
+// against s.baseCtx. Callers must hold s.mu.
+func (s *Service) resizeLocked(n int) {
+	for len(s.workerCancels) < n {
+		id := s.nextWorkerID
+		s.nextWorkerID++
+		workerCtx, cancel := context.WithCancel(s.baseCtx)
+		s.workerCancels[id] = cancel
 		s.wg.Add(1)
-		go s.worker(ctx, i)
+		go s.worker(workerCtx, id)
+	}
+	for id, cancel := range s.workerCancels {
+		if len(s.workerCancels) <= n {
+			break
+		}
+		cancel()
+		delete(s.workerCancels, id)
 	}
+}
+
+func (s *Service) handlerName(t JobType) string {
+	if h, ok := s.handlers[t]; ok {
+		return h.name
+	}
+	return string(t)
+}
+
+// Metrics returns the Prometheus collectors describing queue health, for
+// registration with health.MetricsRegistry.
+func (s *Service) Metrics() *metrics {
+	return s.metrics
+}
+
+// Start begins processing jobs, along with the background scheduler and
+// janitor goroutines that AddPeriodicJob and the backend's visibility
+// timeout tracking rely on.
+func (s *Service) Start(ctx context.Context) error {
+	s.mu.Lock()
+	s.baseCtx = ctx
+	log.Printf("Starting %d queue workers", s.workers)
+	s.resizeLocked(s.workers)
+	s.mu.Unlock()
+
+	s.wg.Add(2)
+	go s.runScheduler(ctx)
+	go s.runJanitor(ctx)
 
 	s.wg.Wait()
 	return nil
 }
 
-// AddJob adds a job to the queue
+// AddPeriodicJob registers job to run on the schedule described by spec, a
+// standard 5-field cron expression (minute hour day-of-month month
+// day-of-week). Start's scheduler goroutine enqueues due occurrences; on
+// backends shared across replicas (electable), only the elected leader
+// scans for them, and each occurrence is claimed atomically so an overlap
+// between the outgoing and incoming leader can't enqueue it twice.
+func (s *Service) AddPeriodicJob(name, spec string, job Job) error {
+	if job.MaxRetry == 0 {
+		job.MaxRetry = 5
+	}
+	if job.Priority == 0 {
+		job.Priority = int(job.priorityClass())
+	}
+	return s.backend.Schedule(context.Background(), name, spec, job)
+}
+
+// runScheduler periodically enqueues due periodic job occurrences. On
+// backends that implement electable, it only does so while holding the
+// leader election, so that many replicas sharing one backend don't all scan
+// and enqueue the same occurrence.
+func (s *Service) runScheduler(ctx context.Context) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(schedulerInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if elect, ok := s.backend.(electable); ok {
+			leader, release, err := elect.electLeader(ctx)
+			if err != nil {
+				log.Printf("scheduler: leader election: %v", err)
+				continue
+			}
+			if !leader {
+				release()
+				continue
+			}
+			s.enqueueDuePeriodic(ctx)
+			release()
+			continue
+		}
+
+		s.enqueueDuePeriodic(ctx)
+	}
+}
+
+func (s *Service) enqueueDuePeriodic(ctx context.Context) {
+	due, err := s.backend.DuePeriodic(ctx, time.Now())
+	if err != nil {
+		log.Printf("scheduler: scan periodic jobs: %v", err)
+		return
+	}
+	for _, job := range due {
+		if err := s.Submit(ctx, &job); err != nil {
+			log.Printf("scheduler: enqueue periodic occurrence: %v", err)
+		}
+	}
+}
+
+// runJanitor periodically asks the backend to requeue in-progress jobs
+// whose visibility timeout lapsed. Backends that don't need this (their
+// claim already recovers stale in-progress jobs, see kvBackend.Dequeue)
+// simply don't implement reclaimer.
+func (s *Service) runJanitor(ctx context.Context) {
+	defer s.wg.Done()
+
+	reclaim, ok := s.backend.(reclaimer)
+	if !ok {
+		return
+	}
+
+	ticker := time.NewTicker(janitorInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := reclaim.reclaimExpired(ctx); err != nil {
+				log.Printf("janitor: reclaim expired jobs: %v", err)
+			}
+		}
+	}
+}
+
+// Submit persists job and makes it claimable by a worker. If job.ID is
+// empty one is generated. If job.IdempotencyKey is set and has already been
+// used for this JobType, ErrDuplicateJob is returned and job is left
+// unmodified on disk.
+func (s *Service) Submit(ctx context.Context, job *Job) error {
+	if job.ID == "" {
+		job.ID = generateJobID()
+	}
+	if job.MaxRetry == 0 {
+		job.MaxRetry = 5
+	}
+	if job.Priority == 0 {
+		job.Priority = int(job.priorityClass())
+	}
+	job.Status = JobStatusPending
+	job.Created = time.Now()
+	job.UpdatedAt = time.Now()
+	job.Payload = tracing.InjectIntoPayload(ctx, job.Payload)
+
+	if err := s.backend.Enqueue(ctx, *job); err != nil {
+		return err
+	}
+
+	s.metrics.depth.WithLabelValues(priorityLabel(job.priorityClass())).Inc()
+	s.metrics.depthByType.WithLabelValues(string(job.Type)).Inc()
+	s.wake()
+	return nil
+}
+
+// AddJob is the synchronous convenience form of Submit used by callers that
+// don't need the generated ID back (kept for compatibility with the
+// in-process callers this queue originally had).
 func (s *Service) AddJob(job Job) error {
+	return s.Submit(context.Background(), &job)
+}
+
+// GetJobStatus returns the current persisted state of a submitted job.
+func (s *Service) GetJobStatus(ctx context.Context, id string) (*Job, error) {
+	return s.backend.Get(ctx, id)
+}
+
+func (s *Service) wake() {
 	select {
-	case s.jobs <- job:
-		return nil
+	case s.notify <- struct{}{}:
 	default:
-		return ErrQueueFull
 	}
 }
 
-// worker processes jobs from the queue
+// worker repeatedly claims the highest-priority claimable job and processes
+// it, waking on either a ticker (to catch jobs whose VisibleAt just elapsed)
+// or a Submit-triggered notification.
 func (s *Service) worker(ctx context.Context, id int) {
 	defer s.wg.Done()
-
 	log.Printf("Worker %d started", id)
 
+	ticker := time.NewTicker(250 * time.Millisecond)
+	defer ticker.Stop()
+
+	workerID := fmt.Sprintf("worker-%d", id)
+
 	for {
 		select {
 		case <-ctx.Done():
 			log.Printf("Worker %d stopping", id)
 			return
-		case job := <-s.jobs:
-			s.processJob(ctx, job)
+		case <-ticker.C:
+		case <-s.notify:
+		}
+
+		for {
+			job, ok, err := s.backend.Dequeue(ctx, workerID)
+			if err != nil {
+				log.Printf("worker %d: dequeue: %v", id, err)
+				break
+			}
+			if !ok {
+				break
+			}
+			s.metrics.depth.WithLabelValues(priorityLabel(job.priorityClass())).Dec()
+			s.metrics.depthByType.WithLabelValues(string(job.Type)).Dec()
+
+			var release func()
+			if limiter := s.limiterFor(job.Type); limiter != nil {
+				admitted, r := limiter.tryAdmit()
+				if !admitted {
+					s.metrics.starvation.WithLabelValues(string(job.Type)).Inc()
+					s.deferJob(ctx, job)
+					continue
+				}
+				release = r
+			}
+
+			s.metrics.waitSeconds.WithLabelValues(string(job.Type)).Observe(time.Since(job.Created).Seconds())
+			s.metrics.inFlight.Inc()
+			s.runJob(ctx, job)
+			if release != nil {
+				release()
+			}
 		}
 	}
 }
 
-// processJob handles individual job processing
-func (s *Service) processJob(ctx context.Context, job Job) {
+// deferJob releases a claimed job back to the backend without counting it
+// as a failed attempt, used when ConfigureType admission control declines
+// it even though it was the highest-priority claimable job. It becomes
+// claimable again after a short, Weight-scaled delay so other types get a
+// turn instead of this worker blocking on one that's at capacity.
+func (s *Service) deferJob(ctx context.Context, job *Job) {
+	weight := 0
+	if limiter := s.limiterFor(job.Type); limiter != nil {
+		weight = limiter.opts.Weight
+	}
+
+	job.Status = JobStatusPending
+	job.VisibleAt = time.Now().Add(requeueDelay(weight))
+	job.UpdatedAt = time.Now()
+
+	s.metrics.depth.WithLabelValues(priorityLabel(job.priorityClass())).Inc()
+	s.metrics.depthByType.WithLabelValues(string(job.Type)).Inc()
+
+	if err := s.backend.Nack(ctx, *job); err != nil {
+		log.Printf("worker: defer job %s: %v", job.ID, err)
+	}
+}
+
+// runJob dispatches job to its handler and records the outcome, retrying
+// with exponential backoff until MaxRetry is exceeded, at which point the
+// job is moved to the dead-letter status.
+func (s *Service) runJob(ctx context.Context, job *Job) {
+	defer s.metrics.inFlight.Dec()
+
+	jobCtx := tracing.ExtractFromPayload(ctx, job.Payload)
+	jobCtx, span := tracing.Tracer().Start(jobCtx, "queue.process_job", trace.WithAttributes(
+		attribute.String("job.id", job.ID),
+		attribute.String("job.type", string(job.Type)),
+		attribute.Int("job.retry", job.Retry),
+	))
+	defer span.End()
+
 	log.Printf("Processing job %s of type %s", job.ID, job.Type)
+	err := s.dispatch(jobCtx, *job)
 
-	switch job.Type {
-	case JobTypeCertificateRenewal:
-		s.processCertificateRenewal(ctx, job)
-	case JobTypeCertificateValidation:
-		s.processCertificateValidation(ctx, job)
-	case JobTypeConfigUpdate:
-		s.processConfigUpdate(ctx, job)
-	case JobTypeLogProcessing:
-		s.processLogProcessing(ctx, job)
-	case JobTypeAnalytics:
-		s.processAnalytics(ctx, job)
-	case JobTypeDatabaseCleanup:
-		s.processDatabaseCleanup(ctx, job)
-	case JobTypeIntegration:
-		s.processIntegration(ctx, job)
-	default:
-		log.Printf("Unknown job type: %s", job.Type)
+	job.UpdatedAt = time.Now()
+	if err == nil {
+		job.Status = JobStatusCompleted
+		s.metrics.processed.WithLabelValues(string(job.Type), "success").Inc()
+		if ackErr := s.backend.Ack(ctx, *job); ackErr != nil {
+			log.Printf("runJob: ack completion for %s: %v", job.ID, ackErr)
+		}
+		return
+	}
+
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+
+	job.Retry++
+	job.LastError = err.Error()
+	job.Attempts = append(job.Attempts, Attempt{At: job.UpdatedAt, Error: err.Error()})
+
+	if job.Retry > job.MaxRetry {
+		job.Status = JobStatusDeadLetter
+		s.metrics.deadLetters.Inc()
+		s.metrics.processed.WithLabelValues(string(job.Type), "failure").Inc()
+		log.Printf("Job %s exceeded max retries (%d), moving to dead letter: %v", job.ID, job.MaxRetry, err)
+		s.notifyFailure(ctx, *job)
+	} else {
+		job.Status = JobStatusFailed
+		delay := backoffDelay(job.Retry)
+		job.VisibleAt = time.Now().Add(delay)
+		s.metrics.depth.WithLabelValues(priorityLabel(job.priorityClass())).Inc()
+		s.metrics.depthByType.WithLabelValues(string(job.Type)).Inc()
+		log.Printf("Job %s failed (attempt %d/%d), retrying in %s: %v", job.ID, job.Retry, job.MaxRetry, delay, err)
+	}
+
+	if nackErr := s.backend.Nack(ctx, *job); nackErr != nil {
+		log.Printf("runJob: nack failure for %s: %v", job.ID, nackErr)
 	}
 }
 
+// notifyFailure raises a JobFailureEvent for a job that just exhausted its
+// retry budget. A nil notifier (the default) is a silent no-op.
+func (s *Service) notifyFailure(ctx context.Context, job Job) {
+	if s.notifier == nil {
+		return
+	}
+	event := JobFailureEvent{
+		JobID:       job.ID,
+		JobType:     job.Type,
+		HandlerName: s.handlerName(job.Type),
+		LastError:   job.LastError,
+		Attempts:    job.Attempts,
+	}
+	if err := s.notifier.NotifyJobFailure(ctx, event); err != nil {
+		log.Printf("runJob: notify failure for %s: %v", job.ID, err)
+	}
+}
+
+// dispatch routes job to its registered handler.
+func (s *Service) dispatch(ctx context.Context, job Job) error {
+	h, ok := s.handlers[job.Type]
+	if !ok {
+		return fmt.Errorf("unknown job type: %s", job.Type)
+	}
+	return h.handler.Handle(ctx, job)
+}
+
+// DeadLetters returns jobs currently sitting in the dead-letter status,
+// optionally narrowed by filter, for operators triaging failures.
+func (s *Service) DeadLetters(ctx context.Context, filter DeadLetterFilter) ([]FailedJob, error) {
+	jobs, err := s.backend.DeadLetters(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	failed := make([]FailedJob, 0, len(jobs))
+	for _, job := range jobs {
+		failed = append(failed, FailedJob{Job: job, HandlerName: s.handlerName(job.Type)})
+	}
+	return failed, nil
+}
+
+// Replay resubmits each dead-lettered job in ids as a fresh job, cloning its
+// type/payload/priority and pointing ReplayedFrom at the original job ID so
+// downstream consumers can tell replayed traffic from live traffic. The
+// original dead-lettered job is left in place as an audit trail.
+func (s *Service) Replay(ctx context.Context, ids []string) error {
+	for _, id := range ids {
+		original, err := s.backend.Get(ctx, id)
+		if err != nil {
+			return fmt.Errorf("replay %s: %w", id, err)
+		}
+		if original.Status != JobStatusDeadLetter {
+			return fmt.Errorf("replay %s: not dead-lettered (status %s)", id, original.Status)
+		}
+
+		clone := Job{
+			Type:         original.Type,
+			Payload:      original.Payload,
+			Priority:     original.Priority,
+			MaxRetry:     original.MaxRetry,
+			ReplayedFrom: original.ID,
+		}
+		if err := s.Submit(ctx, &clone); err != nil {
+			return fmt.Errorf("replay %s: %w", id, err)
+		}
+	}
+	return nil
+}
+
 // Job processing methods (placeholders for now)
-func (s *Service) processCertificateRenewal(ctx context.Context, job Job) {
+func (s *Service) processCertificateRenewal(ctx context.Context, job Job) error {
 	// TODO: Implement certificate renewal logic
 	log.Printf("Processing certificate renewal job %s", job.ID)
+	return nil
 }
 
-func (s *Service) processCertificateValidation(ctx context.Context, job Job) {
+func (s *Service) processCertificateValidation(ctx context.Context, job Job) error {
 	// TODO: Implement certificate validation logic
 	log.Printf("Processing certificate validation job %s", job.ID)
+	return nil
 }
 
-func (s *Service) processConfigUpdate(ctx context.Context, job Job) {
+func (s *Service) processConfigUpdate(ctx context.Context, job Job) error {
 	// TODO: Implement configuration update logic
 	log.Printf("Processing config update job %s", job.ID)
+	return nil
 }
 
-func (s *Service) processLogProcessing(ctx context.Context, job Job) {
+func (s *Service) processLogProcessing(ctx context.Context, job Job) error {
 	// TODO: Implement log processing logic
 	log.Printf("Processing log processing job %s", job.ID)
+	return nil
 }
 
-func (s *Service) processAnalytics(ctx context.Context, job Job) {
+func (s *Service) processAnalytics(ctx context.Context, job Job) error {
 	// TODO: Implement analytics logic
 	log.Printf("Processing analytics job %s", job.ID)
+	return nil
 }
 
-func (s *Service) processDatabaseCleanup(ctx context.Context, job Job) {
+func (s *Service) processDatabaseCleanup(ctx context.Context, job Job) error {
 	// TODO: Implement database cleanup logic
 	log.Printf("Processing database cleanup job %s", job.ID)
+	return nil
 }
 
-func (s *Service) processIntegration(ctx context.Context, job Job) {
-	// TODO: Implement integration logic
+func (s *Service) processIntegration(ctx context.Context, job Job) error {
+	// TODO: Wire in an integration.WebhookDeliverer to actually redeliver the
+	// webhook named in job.Payload; see integration.WebhookDeliverer.HandleRetryJob.
 	log.Printf("Processing integration job %s", job.ID)
-}
\ No newline at end of file
+	return nil
+}
+
+func (s *Service) processThreatFeedPoll(ctx context.Context, job Job) error {
+	// TODO: Wire in an integration.FeedProcessor to actually poll the feed
+	// named in job.Payload; see internal/integration.Manager.ProcessSecurityFeeds.
+	log.Printf("Processing threat feed poll job %s", job.ID)
+	return nil
+}
+
+func backoffDelay(attempt int) time.Duration {
+	delay := backoffBase * time.Duration(1<<uint(attempt-1))
+	if delay > backoffMax {
+		delay = backoffMax
+	}
+	jitter := time.Duration(rand.Int63n(int64(delay) / 2))
+	return delay + jitter
+}
+
+func priorityLabel(p PriorityClass) string {
+	switch p {
+	case PriorityEmergency:
+		return "emergency"
+	case PriorityNormal:
+		return "normal"
+	default:
+		return "low"
+	}
+}
+
+func generateJobID() string {
+	buf := make([]byte, 8)
+	if _, err := cryptorand.Read(buf); err != nil {
+		return fmt.Sprintf("job-%d", time.Now().UnixNano())
+	}
+	return "job-" + hex.EncodeToString(buf)
+}
@@ -0,0 +1,117 @@
+//go:build !nobadger
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	badger "github.com/dgraph-io/badger/v4"
+)
+
+func init() {
+	Register("badger", newBadgerKV)
+}
+
+type badgerKV struct {
+	db *badger.DB
+}
+
+func newBadgerKV(ctx context.Context, dsn string) (KV, error) {
+	if dsn == "" {
+		dsn = "./data/badger"
+	}
+	opts := badger.DefaultOptions(dsn).WithLogger(nil)
+	db, err := badger.Open(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &badgerKV{db: db}, nil
+}
+
+func (b *badgerKV) Get(ctx context.Context, key string) ([]byte, error) {
+	var value []byte
+	err := b.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get([]byte(key))
+		if err == badger.ErrKeyNotFound {
+			return ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+		return item.Value(func(v []byte) error {
+			value = append([]byte(nil), v...)
+			return nil
+		})
+	})
+	return value, err
+}
+
+func (b *badgerKV) Put(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return b.db.Update(func(txn *badger.Txn) error {
+		entry := badger.NewEntry([]byte(key), value)
+		if ttl > 0 {
+			entry = entry.WithTTL(ttl)
+		}
+		return txn.SetEntry(entry)
+	})
+}
+
+func (b *badgerKV) Delete(ctx context.Context, key string) error {
+	return b.db.Update(func(txn *badger.Txn) error {
+		return txn.Delete([]byte(key))
+	})
+}
+
+func (b *badgerKV) List(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	err := b.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		prefixBytes := []byte(prefix)
+		for it.Seek(prefixBytes); it.ValidForPrefix(prefixBytes); it.Next() {
+			keys = append(keys, string(it.Item().KeyCopy(nil)))
+		}
+		return nil
+	})
+	return keys, err
+}
+
+func (b *badgerKV) CompareAndSwap(ctx context.Context, key string, oldValue, newValue []byte) (bool, error) {
+	var swapped bool
+	err := b.db.Update(func(txn *badger.Txn) error {
+		item, err := txn.Get([]byte(key))
+		switch {
+		case err == badger.ErrKeyNotFound:
+			if oldValue != nil {
+				return nil
+			}
+		case err != nil:
+			return err
+		default:
+			var current []byte
+			if err := item.Value(func(v []byte) error {
+				current = append([]byte(nil), v...)
+				return nil
+			}); err != nil {
+				return err
+			}
+			if oldValue == nil || !bytes.Equal(current, oldValue) {
+				return nil
+			}
+		}
+
+		if err := txn.Set([]byte(key), newValue); err != nil {
+			return err
+		}
+		swapped = true
+		return nil
+	})
+	return swapped, err
+}
+
+func (b *badgerKV) Close() error { return b.db.Close() }
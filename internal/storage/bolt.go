@@ -0,0 +1,138 @@
+//go:build !nobolt
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+func init() {
+	Register("bolt", newBoltKV)
+}
+
+var boltBucket = []byte("storage")
+
+type boltRecord struct {
+	Value     []byte    `json:"value"`
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+}
+
+type boltKV struct {
+	db *bolt.DB
+}
+
+func newBoltKV(ctx context.Context, dsn string) (KV, error) {
+	if dsn == "" {
+		dsn = "./data/certificates.bolt"
+	}
+	db, err := bolt.Open(dsn, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(boltBucket)
+		return err
+	}); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &boltKV{db: db}, nil
+}
+
+func (b *boltKV) read(tx *bolt.Tx, key string) (*boltRecord, error) {
+	raw := tx.Bucket(boltBucket).Get([]byte(key))
+	if raw == nil {
+		return nil, ErrNotFound
+	}
+	var rec boltRecord
+	if err := json.Unmarshal(raw, &rec); err != nil {
+		return nil, err
+	}
+	if !rec.ExpiresAt.IsZero() && time.Now().After(rec.ExpiresAt) {
+		return nil, ErrNotFound
+	}
+	return &rec, nil
+}
+
+func (b *boltKV) Get(ctx context.Context, key string) ([]byte, error) {
+	var value []byte
+	err := b.db.View(func(tx *bolt.Tx) error {
+		rec, err := b.read(tx, key)
+		if err != nil {
+			return err
+		}
+		value = rec.Value
+		return nil
+	})
+	return value, err
+}
+
+func (b *boltKV) Put(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	rec := boltRecord{Value: value}
+	if ttl > 0 {
+		rec.ExpiresAt = time.Now().Add(ttl)
+	}
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucket).Put([]byte(key), data)
+	})
+}
+
+func (b *boltKV) Delete(ctx context.Context, key string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucket).Delete([]byte(key))
+	})
+}
+
+func (b *boltKV) List(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	err := b.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(boltBucket).Cursor()
+		prefixBytes := []byte(prefix)
+		for k, _ := c.Seek(prefixBytes); k != nil && bytes.HasPrefix(k, prefixBytes); k, _ = c.Next() {
+			keys = append(keys, string(k))
+		}
+		return nil
+	})
+	return keys, err
+}
+
+func (b *boltKV) CompareAndSwap(ctx context.Context, key string, oldValue, newValue []byte) (bool, error) {
+	var swapped bool
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		current, err := b.read(tx, key)
+		switch {
+		case err == ErrNotFound:
+			if oldValue != nil {
+				return nil
+			}
+		case err != nil:
+			return err
+		default:
+			if oldValue == nil || !bytes.Equal(current.Value, oldValue) {
+				return nil
+			}
+		}
+
+		data, err := json.Marshal(boltRecord{Value: newValue})
+		if err != nil {
+			return err
+		}
+		if err := tx.Bucket(boltBucket).Put([]byte(key), data); err != nil {
+			return err
+		}
+		swapped = true
+		return nil
+	})
+	return swapped, err
+}
+
+func (b *boltKV) Close() error { return b.db.Close() }
@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/awesomeapibrasil/gateway-worker/internal/certificate"
+)
+
+const certKeyPrefix = "certificate/"
+
+func certKey(domain string) string { return certKeyPrefix + domain }
+
+// certificateStore adapts a KV backend to certificate.Storage.
+type certificateStore struct {
+	kv KV
+}
+
+// NewCertificateStore wraps kv as a certificate.Storage, so any registered
+// driver can back certificate.Manager.
+func NewCertificateStore(kv KV) certificate.Storage {
+	return &certificateStore{kv: kv}
+}
+
+func (c *certificateStore) Store(ctx context.Context, cert *certificate.Certificate) error {
+	data, err := json.Marshal(cert)
+	if err != nil {
+		return err
+	}
+
+	var ttl time.Duration
+	if cert.Type == certificate.CertificateTypeTemporary {
+		ttl = time.Until(cert.Expiry)
+	}
+	return c.kv.Put(ctx, certKey(cert.Domain), data, ttl)
+}
+
+func (c *certificateStore) Retrieve(ctx context.Context, domain string) (*certificate.Certificate, error) {
+	data, err := c.kv.Get(ctx, certKey(domain))
+	if err != nil {
+		if err == ErrNotFound {
+			return nil, fmt.Errorf("certificate for %s: %w", domain, err)
+		}
+		return nil, err
+	}
+
+	var cert certificate.Certificate
+	if err := json.Unmarshal(data, &cert); err != nil {
+		return nil, err
+	}
+	return &cert, nil
+}
+
+func (c *certificateStore) List(ctx context.Context) ([]*certificate.Certificate, error) {
+	keys, err := c.kv.List(ctx, certKeyPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	certs := make([]*certificate.Certificate, 0, len(keys))
+	for _, key := range keys {
+		data, err := c.kv.Get(ctx, key)
+		if err != nil {
+			// Deleted or TTL-expired between List and Get; skip it.
+			continue
+		}
+		var cert certificate.Certificate
+		if err := json.Unmarshal(data, &cert); err != nil {
+			return nil, err
+		}
+		certs = append(certs, &cert)
+	}
+	return certs, nil
+}
+
+func (c *certificateStore) Delete(ctx context.Context, domain string) error {
+	return c.kv.Delete(ctx, certKey(domain))
+}
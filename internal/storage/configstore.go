@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/awesomeapibrasil/gateway-worker/internal/config"
+)
+
+const configKeyPrefix = "config/"
+
+func configKey(configType, version string) string {
+	return fmt.Sprintf("%s%s/%s", configKeyPrefix, configType, version)
+}
+
+// configurationStore adapts a KV backend to config.Storage.
+type configurationStore struct {
+	kv KV
+}
+
+// NewConfigurationStore wraps kv as a config.Storage, so any registered
+// driver can back config.Manager.
+func NewConfigurationStore(kv KV) config.Storage {
+	return &configurationStore{kv: kv}
+}
+
+func (c *configurationStore) Store(ctx context.Context, cfg *config.Configuration) error {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+	return c.kv.Put(ctx, configKey(string(cfg.Type), cfg.Version), data, 0)
+}
+
+func (c *configurationStore) Retrieve(ctx context.Context, configType, version string) (*config.Configuration, error) {
+	data, err := c.kv.Get(ctx, configKey(configType, version))
+	if err != nil {
+		if err == ErrNotFound {
+			return nil, fmt.Errorf("configuration %s@%s: %w", configType, version, err)
+		}
+		return nil, err
+	}
+
+	var cfg config.Configuration
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+func (c *configurationStore) List(ctx context.Context, configType string) ([]*config.Configuration, error) {
+	keys, err := c.kv.List(ctx, configKeyPrefix+configType+"/")
+	if err != nil {
+		return nil, err
+	}
+
+	configs := make([]*config.Configuration, 0, len(keys))
+	for _, key := range keys {
+		data, err := c.kv.Get(ctx, key)
+		if err != nil {
+			continue
+		}
+		var cfg config.Configuration
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return nil, err
+		}
+		configs = append(configs, &cfg)
+	}
+	return configs, nil
+}
+
+// GetLatest returns the configuration with the highest version string for
+// configType. generateVersion formats versions as sortable timestamps
+// (YYYYMMDDHHMMSS), so lexicographic ordering is chronological ordering.
+func (c *configurationStore) GetLatest(ctx context.Context, configType string) (*config.Configuration, error) {
+	configs, err := c.List(ctx, configType)
+	if err != nil {
+		return nil, err
+	}
+	if len(configs) == 0 {
+		return nil, fmt.Errorf("no configuration stored for %s: %w", configType, ErrNotFound)
+	}
+
+	sort.Slice(configs, func(i, j int) bool { return configs[i].Version < configs[j].Version })
+	return configs[len(configs)-1], nil
+}
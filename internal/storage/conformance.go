@@ -0,0 +1,126 @@
+package storage
+
+import (
+	"context"
+	"time"
+)
+
+// TestingT is the subset of *testing.T that RunConformance needs, so this
+// file doesn't have to import "testing" directly and each driver's own
+// _test.go can pass its *testing.T straight through.
+type TestingT interface {
+	Helper()
+	Fatalf(format string, args ...interface{})
+}
+
+// RunConformance exercises the behaviors every KV driver must implement
+// identically: round-trip, prefix listing, atomic compare-and-swap, and TTL
+// expiry. Driver packages call this from their own tests, e.g.:
+//
+//	func TestBoltConformance(t *testing.T) {
+//		kv, _ := storage.Open(context.Background(), "bolt", t.TempDir()+"/db")
+//		defer kv.Close()
+//		storage.RunConformance(t, kv)
+//	}
+func RunConformance(t TestingT, kv KV) {
+	t.Helper()
+	ctx := context.Background()
+
+	roundTrip(t, ctx, kv)
+	list(t, ctx, kv)
+	compareAndSwap(t, ctx, kv)
+	ttlExpiry(t, ctx, kv)
+}
+
+func roundTrip(t TestingT, ctx context.Context, kv KV) {
+	t.Helper()
+
+	key := "conformance/round-trip"
+	if err := kv.Put(ctx, key, []byte("value"), 0); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	got, err := kv.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if string(got) != "value" {
+		t.Fatalf("got %q, want %q", got, "value")
+	}
+	if err := kv.Delete(ctx, key); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := kv.Get(ctx, key); err != ErrNotFound {
+		t.Fatalf("get after delete: got err %v, want ErrNotFound", err)
+	}
+}
+
+func list(t TestingT, ctx context.Context, kv KV) {
+	t.Helper()
+
+	prefix := "conformance/list/"
+	want := map[string]bool{prefix + "a": true, prefix + "b": true, prefix + "c": true}
+	for key := range want {
+		if err := kv.Put(ctx, key, []byte("x"), 0); err != nil {
+			t.Fatalf("put %s: %v", key, err)
+		}
+	}
+	defer func() {
+		for key := range want {
+			kv.Delete(ctx, key)
+		}
+	}()
+
+	keys, err := kv.List(ctx, prefix)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(keys) != len(want) {
+		t.Fatalf("list returned %d keys, want %d", len(keys), len(want))
+	}
+	for _, key := range keys {
+		if !want[key] {
+			t.Fatalf("list returned unexpected key %s", key)
+		}
+	}
+}
+
+func compareAndSwap(t TestingT, ctx context.Context, kv KV) {
+	t.Helper()
+
+	key := "conformance/cas"
+	defer kv.Delete(ctx, key)
+
+	ok, err := kv.CompareAndSwap(ctx, key, nil, []byte("v1"))
+	if err != nil || !ok {
+		t.Fatalf("create-if-absent CAS: ok=%v err=%v", ok, err)
+	}
+
+	ok, err = kv.CompareAndSwap(ctx, key, []byte("wrong"), []byte("v2"))
+	if err != nil || ok {
+		t.Fatalf("CAS with wrong oldValue should fail: ok=%v err=%v", ok, err)
+	}
+
+	ok, err = kv.CompareAndSwap(ctx, key, []byte("v1"), []byte("v2"))
+	if err != nil || !ok {
+		t.Fatalf("CAS with correct oldValue should succeed: ok=%v err=%v", ok, err)
+	}
+
+	got, err := kv.Get(ctx, key)
+	if err != nil || string(got) != "v2" {
+		t.Fatalf("post-CAS value = %q, err = %v, want v2", got, err)
+	}
+}
+
+func ttlExpiry(t TestingT, ctx context.Context, kv KV) {
+	t.Helper()
+
+	key := "conformance/ttl"
+	if err := kv.Put(ctx, key, []byte("temporary"), 50*time.Millisecond); err != nil {
+		t.Fatalf("put with ttl: %v", err)
+	}
+	time.Sleep(150 * time.Millisecond)
+
+	if _, err := kv.Get(ctx, key); err != ErrNotFound {
+		t.Fatalf("get after ttl expiry: got err %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,38 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/awesomeapibrasil/gateway-worker/internal/integration"
+)
+
+const feedCursorKeyPrefix = "feed/cursor/"
+
+func feedCursorKey(feedName string) string { return feedCursorKeyPrefix + feedName }
+
+// feedCursorStore adapts a KV backend to integration.CursorStore.
+type feedCursorStore struct {
+	kv KV
+}
+
+// NewFeedCursorStore wraps kv as an integration.CursorStore, so threat feed
+// poll cursors persist across restarts through any registered driver, the
+// same way certificates and configuration do.
+func NewFeedCursorStore(kv KV) integration.CursorStore {
+	return &feedCursorStore{kv: kv}
+}
+
+func (f *feedCursorStore) GetCursor(ctx context.Context, feedName string) (string, error) {
+	data, err := f.kv.Get(ctx, feedCursorKey(feedName))
+	if err != nil {
+		if err == ErrNotFound {
+			return "", nil
+		}
+		return "", err
+	}
+	return string(data), nil
+}
+
+func (f *feedCursorStore) SetCursor(ctx context.Context, feedName, cursor string) error {
+	return f.kv.Put(ctx, feedCursorKey(feedName), []byte(cursor), 0)
+}
@@ -0,0 +1,162 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+func init() {
+	Register("fs", newFSKV)
+}
+
+// fsRecord is the on-disk envelope written for every key, so TTLs survive
+// process restarts.
+type fsRecord struct {
+	Value     []byte    `json:"value"`
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+}
+
+// fsKV is a single-node development driver that stores each key as one file
+// under dir, named by a filesystem-safe encoding of the key.
+type fsKV struct {
+	mu  sync.Mutex
+	dir string
+}
+
+func newFSKV(ctx context.Context, dsn string) (KV, error) {
+	dir := dsn
+	if dir == "" {
+		dir = "./data"
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &fsKV{dir: dir}, nil
+}
+
+func (f *fsKV) path(key string) string {
+	return filepath.Join(f.dir, encodeKey(key)+".json")
+}
+
+func encodeKey(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "/", "__"), ":", "--")
+}
+
+func decodeKey(name string) string {
+	name = strings.TrimSuffix(name, ".json")
+	return strings.ReplaceAll(strings.ReplaceAll(name, "--", ":"), "__", "/")
+}
+
+func (f *fsKV) read(key string) (*fsRecord, error) {
+	data, err := os.ReadFile(f.path(key))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	var rec fsRecord
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, err
+	}
+	if !rec.ExpiresAt.IsZero() && time.Now().After(rec.ExpiresAt) {
+		os.Remove(f.path(key))
+		return nil, ErrNotFound
+	}
+	return &rec, nil
+}
+
+func (f *fsKV) Get(ctx context.Context, key string) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	rec, err := f.read(key)
+	if err != nil {
+		return nil, err
+	}
+	return rec.Value, nil
+}
+
+func (f *fsKV) Put(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	rec := fsRecord{Value: value}
+	if ttl > 0 {
+		rec.ExpiresAt = time.Now().Add(ttl)
+	}
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(f.path(key), data, 0o600)
+}
+
+func (f *fsKV) Delete(ctx context.Context, key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	err := os.Remove(f.path(key))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (f *fsKV) List(ctx context.Context, prefix string) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	entries, err := os.ReadDir(f.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		key := decodeKey(e.Name())
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+func (f *fsKV) CompareAndSwap(ctx context.Context, key string, oldValue, newValue []byte) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	current, err := f.read(key)
+	switch {
+	case err == ErrNotFound:
+		if oldValue != nil {
+			return false, nil
+		}
+	case err != nil:
+		return false, err
+	default:
+		if oldValue == nil || !bytes.Equal(current.Value, oldValue) {
+			return false, nil
+		}
+	}
+
+	data, err := json.Marshal(fsRecord{Value: newValue})
+	if err != nil {
+		return false, err
+	}
+	if err := os.WriteFile(f.path(key), data, 0o600); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (f *fsKV) Close() error { return nil }
@@ -0,0 +1,121 @@
+//go:build !nopostgres
+
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func init() {
+	Register("postgres", newPostgresKV)
+}
+
+const postgresSchema = `
+CREATE TABLE IF NOT EXISTS storage_kv (
+	key        TEXT PRIMARY KEY,
+	value      BYTEA NOT NULL,
+	expires_at TIMESTAMPTZ
+);
+`
+
+type postgresKV struct {
+	pool *pgxpool.Pool
+}
+
+func newPostgresKV(ctx context.Context, dsn string) (KV, error) {
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := pool.Exec(ctx, postgresSchema); err != nil {
+		pool.Close()
+		return nil, err
+	}
+	return &postgresKV{pool: pool}, nil
+}
+
+func (p *postgresKV) Get(ctx context.Context, key string) ([]byte, error) {
+	var value []byte
+	err := p.pool.QueryRow(ctx,
+		`SELECT value FROM storage_kv WHERE key = $1 AND (expires_at IS NULL OR expires_at > now())`,
+		key,
+	).Scan(&value)
+	if err != nil {
+		return nil, ErrNotFound
+	}
+	return value, nil
+}
+
+func (p *postgresKV) Put(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	var expiresAt *time.Time
+	if ttl > 0 {
+		t := time.Now().Add(ttl)
+		expiresAt = &t
+	}
+	_, err := p.pool.Exec(ctx, `
+		INSERT INTO storage_kv (key, value, expires_at) VALUES ($1, $2, $3)
+		ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value, expires_at = EXCLUDED.expires_at
+	`, key, value, expiresAt)
+	return err
+}
+
+func (p *postgresKV) Delete(ctx context.Context, key string) error {
+	_, err := p.pool.Exec(ctx, `DELETE FROM storage_kv WHERE key = $1`, key)
+	return err
+}
+
+func (p *postgresKV) List(ctx context.Context, prefix string) ([]string, error) {
+	rows, err := p.pool.Query(ctx,
+		`SELECT key FROM storage_kv WHERE key LIKE $1 AND (expires_at IS NULL OR expires_at > now())`,
+		prefix+"%",
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+// CompareAndSwap relies on Postgres' single-statement atomicity: the UPDATE
+// (or INSERT, for the create-if-absent case) only affects a row if the
+// WHERE/ON-CONFLICT condition matches, so concurrent callers never both
+// observe a successful swap for the same transition.
+func (p *postgresKV) CompareAndSwap(ctx context.Context, key string, oldValue, newValue []byte) (bool, error) {
+	var tag int64
+	if oldValue == nil {
+		res, err := p.pool.Exec(ctx, `
+			INSERT INTO storage_kv (key, value) VALUES ($1, $2)
+			ON CONFLICT (key) DO NOTHING
+		`, key, newValue)
+		if err != nil {
+			return false, err
+		}
+		tag = res.RowsAffected()
+	} else {
+		res, err := p.pool.Exec(ctx, `
+			UPDATE storage_kv SET value = $2 WHERE key = $1 AND value = $3
+		`, key, newValue, oldValue)
+		if err != nil {
+			return false, err
+		}
+		tag = res.RowsAffected()
+	}
+	return tag == 1, nil
+}
+
+func (p *postgresKV) Close() error {
+	p.pool.Close()
+	return nil
+}
@@ -0,0 +1,87 @@
+//go:build !noredis
+
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func init() {
+	Register("redis", newRedisKV)
+}
+
+type redisKV struct {
+	client *redis.Client
+}
+
+func newRedisKV(ctx context.Context, dsn string) (KV, error) {
+	opts, err := redis.ParseURL(dsn)
+	if err != nil {
+		return nil, err
+	}
+	client := redis.NewClient(opts)
+	if err := client.Ping(ctx).Err(); err != nil {
+		return nil, err
+	}
+	return &redisKV{client: client}, nil
+}
+
+func (r *redisKV) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := r.client.Get(ctx, key).Bytes()
+	if err == redis.Nil {
+		return nil, ErrNotFound
+	}
+	return value, err
+}
+
+func (r *redisKV) Put(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return r.client.Set(ctx, key, value, ttl).Err()
+}
+
+func (r *redisKV) Delete(ctx context.Context, key string) error {
+	return r.client.Del(ctx, key).Err()
+}
+
+func (r *redisKV) List(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	iter := r.client.Scan(ctx, 0, prefix+"*", 0).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	return keys, iter.Err()
+}
+
+// compareAndSwapScript is a Lua script so the read-compare-write is atomic
+// on the Redis server, matching what the other drivers get from a
+// transaction or a single conditional statement.
+const compareAndSwapScript = `
+local current = redis.call("GET", KEYS[1])
+if ARGV[1] == "" then
+	if current then
+		return 0
+	end
+else
+	if current == false or current ~= ARGV[1] then
+		return 0
+	end
+end
+redis.call("SET", KEYS[1], ARGV[2])
+return 1
+`
+
+func (r *redisKV) CompareAndSwap(ctx context.Context, key string, oldValue, newValue []byte) (bool, error) {
+	old := ""
+	if oldValue != nil {
+		old = string(oldValue)
+	}
+	result, err := r.client.Eval(ctx, compareAndSwapScript, []string{key}, old, string(newValue)).Int()
+	if err != nil {
+		return false, err
+	}
+	return result == 1, nil
+}
+
+func (r *redisKV) Close() error { return r.client.Close() }
@@ -0,0 +1,97 @@
+// Package storage provides a pluggable key-value abstraction backing
+// certificate and configuration persistence. Concrete drivers register
+// themselves from build-tag-guarded files so operators can exclude the ones
+// they don't need (nobolt, nobadger, nopostgres, noredis) and shrink the
+// binary; the filesystem driver has no tag and is always available as the
+// single-node development default.
+package storage
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNotFound is returned by Get when the key does not exist.
+var ErrNotFound = errors.New("storage: key not found")
+
+// KV is the common abstraction every storage driver implements.
+type KV interface {
+	// Get returns the value stored at key, or ErrNotFound.
+	Get(ctx context.Context, key string) ([]byte, error)
+
+	// Put stores value at key. A zero ttl means the entry never expires;
+	// drivers that cannot expire keys natively (e.g. Postgres without a
+	// background sweeper) must still honor ttl on Get by treating an
+	// expired entry as ErrNotFound.
+	Put(ctx context.Context, key string, value []byte, ttl time.Duration) error
+
+	// Delete removes key. Deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+
+	// List returns every key with the given prefix, in no particular order.
+	List(ctx context.Context, prefix string) ([]string, error)
+
+	// CompareAndSwap atomically replaces the value at key with newValue only
+	// if the current value equals oldValue (nil oldValue means "key must not
+	// exist yet"). It reports whether the swap happened, which every driver
+	// must implement as a genuine atomic operation so version bumps never
+	// race across worker replicas.
+	CompareAndSwap(ctx context.Context, key string, oldValue, newValue []byte) (bool, error)
+
+	// Close releases any underlying connections/handles.
+	Close() error
+}
+
+// Driver constructs a KV from a driver-specific data source name (a file
+// path, a DSN, a host:port — whatever the driver expects).
+type Driver func(ctx context.Context, dsn string) (KV, error)
+
+var drivers = make(map[string]Driver)
+
+// Register makes a driver available under name. Build-tag-guarded driver
+// files call this from an init() func, so excluding a tag simply removes it
+// from this map rather than breaking the build.
+func Register(name string, driver Driver) {
+	drivers[name] = driver
+}
+
+// Open constructs the KV registered under backend (e.g. "bolt", "badger",
+// "postgres", "redis", "fs"), connecting it with dsn.
+func Open(ctx context.Context, backend, dsn string) (KV, error) {
+	driver, ok := drivers[backend]
+	if !ok {
+		return nil, &UnknownBackendError{Backend: backend, Available: availableBackends()}
+	}
+	return driver(ctx, dsn)
+}
+
+// UnknownBackendError is returned by Open when backend isn't registered,
+// typically because the binary was built with that driver's exclusion tag.
+type UnknownBackendError struct {
+	Backend   string
+	Available []string
+}
+
+func (e *UnknownBackendError) Error() string {
+	return "storage: unknown backend " + e.Backend + " (available: " + joinStrings(e.Available, ", ") + ")"
+}
+
+func availableBackends() []string {
+	names := make([]string, 0, len(drivers))
+	for name := range drivers {
+		names = append(names, name)
+	}
+	return names
+}
+
+func joinStrings(items []string, sep string) string {
+	out := ""
+	for i, s := range items {
+		if i > 0 {
+			out += sep
+		}
+		out += s
+	}
+	return out
+}
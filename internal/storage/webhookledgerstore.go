@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/awesomeapibrasil/gateway-worker/internal/integration"
+)
+
+const webhookLedgerPrefix = "webhook/ledger/"
+
+// webhookLedgerKeepFor bounds how long a delivery attempt stays in the
+// ledger, so a frequently-retried webhook doesn't grow its history forever.
+const webhookLedgerKeepFor = 30 * 24 * time.Hour
+
+func webhookLedgerKey(webhookID string, at time.Time) string {
+	return fmt.Sprintf("%s%s/%d", webhookLedgerPrefix, webhookID, at.UnixNano())
+}
+
+// webhookLedgerStore adapts a KV backend to integration.WebhookLedger.
+type webhookLedgerStore struct {
+	kv KV
+}
+
+// NewWebhookLedgerStore wraps kv as an integration.WebhookLedger, so
+// APIClient.DeliveryHistory survives restarts through any registered
+// driver, the same way feed poll cursors do.
+func NewWebhookLedgerStore(kv KV) integration.WebhookLedger {
+	return &webhookLedgerStore{kv: kv}
+}
+
+func (s *webhookLedgerStore) RecordDelivery(ctx context.Context, webhookID string, attempt integration.DeliveryAttempt) error {
+	data, err := json.Marshal(attempt)
+	if err != nil {
+		return err
+	}
+	return s.kv.Put(ctx, webhookLedgerKey(webhookID, attempt.At), data, webhookLedgerKeepFor)
+}
+
+func (s *webhookLedgerStore) ListDeliveries(ctx context.Context, webhookID string, since time.Time) ([]integration.DeliveryAttempt, error) {
+	keys, err := s.kv.List(ctx, webhookLedgerPrefix+webhookID+"/")
+	if err != nil {
+		return nil, err
+	}
+
+	var out []integration.DeliveryAttempt
+	for _, key := range keys {
+		data, err := s.kv.Get(ctx, key)
+		if err != nil {
+			// Expired or deleted between List and Get; skip it.
+			continue
+		}
+		var attempt integration.DeliveryAttempt
+		if err := json.Unmarshal(data, &attempt); err != nil {
+			return nil, err
+		}
+		if attempt.At.Before(since) {
+			continue
+		}
+		out = append(out, attempt)
+	}
+	return out, nil
+}
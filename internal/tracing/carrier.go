@@ -0,0 +1,49 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// traceparentPayloadKey is the job payload field the traceparent (and any
+// tracestate) travels under. Prefixed with an underscore so it reads as
+// metadata rather than job input if a handler ranges over the payload map.
+const traceparentPayloadKey = "_traceparent"
+const tracestatePayloadKey = "_tracestate"
+
+// InjectIntoPayload serializes ctx's trace context into a job payload map so
+// it survives the job sitting in the persistent queue between Submit and
+// whenever a worker dequeues it. Returns payload (creating one if nil) for
+// convenient chaining at the call site.
+func InjectIntoPayload(ctx context.Context, payload map[string]interface{}) map[string]interface{} {
+	if payload == nil {
+		payload = make(map[string]interface{})
+	}
+
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+
+	if tp := carrier.Get("traceparent"); tp != "" {
+		payload[traceparentPayloadKey] = tp
+	}
+	if ts := carrier.Get("tracestate"); ts != "" {
+		payload[tracestatePayloadKey] = ts
+	}
+	return payload
+}
+
+// ExtractFromPayload restores the trace context previously injected by
+// InjectIntoPayload, so a span created while processing the dequeued job
+// appears as a child of the span that submitted it.
+func ExtractFromPayload(ctx context.Context, payload map[string]interface{}) context.Context {
+	carrier := propagation.MapCarrier{}
+	if tp, ok := payload[traceparentPayloadKey].(string); ok {
+		carrier.Set("traceparent", tp)
+	}
+	if ts, ok := payload[tracestatePayloadKey].(string); ok {
+		carrier.Set("tracestate", ts)
+	}
+	return otel.GetTextMapPropagator().Extract(ctx, carrier)
+}
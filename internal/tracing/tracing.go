@@ -0,0 +1,87 @@
+// Package tracing wires the worker's gRPC, queue, and certificate flows
+// into OpenTelemetry: an OTLP exporter for spans, and propagation helpers so
+// trace context survives a hop through the persistent job queue.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// defaultSampleRatio is used when OTEL_TRACES_SAMPLER_ARG is unset, trading
+// full visibility for overhead on every RPC and queue job.
+const defaultSampleRatio = 0.1
+
+// Init configures the global TracerProvider and text-map propagator. If
+// OTEL_EXPORTER_OTLP_ENDPOINT is unset, tracing is a no-op (spans are
+// created but dropped) so local development doesn't need a collector
+// running. The returned shutdown func flushes and closes the exporter and
+// should be deferred by the caller.
+func Init(ctx context.Context, serviceName string) (func(context.Context) error, error) {
+	endpoint := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+	if endpoint == "" {
+		otel.SetTextMapPropagator(propagation.TraceContext{})
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracegrpc.New(ctx, otlptracegrpc.WithEndpoint(endpoint), otlptracegrpc.WithInsecure())
+	if err != nil {
+		return nil, fmt.Errorf("create OTLP trace exporter: %w", err)
+	}
+
+	res, err := sdkresource.New(ctx,
+		sdkresource.WithAttributes(semconv.ServiceName(serviceName)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("build trace resource: %w", err)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(samplerFromEnv()),
+	)
+
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	return tp.Shutdown, nil
+}
+
+// samplerFromEnv honors OTEL_TRACES_SAMPLER/OTEL_TRACES_SAMPLER_ARG
+// (https://opentelemetry.io/docs/specs/otel/configuration/sdk-environment-variables/),
+// defaulting to a parent-based ratio sampler so a caller's sampling
+// decision propagates instead of being re-rolled at every hop.
+func samplerFromEnv() sdktrace.Sampler {
+	ratio := defaultSampleRatio
+	if v := os.Getenv("OTEL_TRACES_SAMPLER_ARG"); v != "" {
+		if r, err := strconv.ParseFloat(v, 64); err == nil {
+			ratio = r
+		}
+	}
+
+	switch os.Getenv("OTEL_TRACES_SAMPLER") {
+	case "always_on":
+		return sdktrace.AlwaysSample()
+	case "always_off":
+		return sdktrace.NeverSample()
+	default:
+		return sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))
+	}
+}
+
+// Tracer returns the worker's named tracer. Call sites use this rather than
+// otel.Tracer directly so the instrumentation name stays consistent.
+func Tracer() trace.Tracer {
+	return otel.Tracer("github.com/awesomeapibrasil/gateway-worker")
+}